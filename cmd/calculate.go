@@ -2,10 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"giftcalc/internal/application/calculation"
 	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/config"
+	"giftcalc/internal/infrastructure/schema"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -19,218 +23,199 @@ var calculateCmd = &cobra.Command{
 func init() {
 	calculateCmd.
 		Flags().
-		String("children", "", "Файл с данными о детях (JSON)")
+		String("children", "", "Файл с данными о детях (JSON), по умолчанию <data-dir>/children.json")
 	calculateCmd.
-		Flags().String("catalog", "", "Файл каталога подарков")
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	calculateCmd.
+		Flags().String("wishes", "", "Файл с пожеланиями детей")
 	calculateCmd.
 		Flags().String("report", "report.json", "Файл отчета")
 	calculateCmd.
-		Flags().Float32("maxBudget", 1000, "Максимальный бюджет для одного подарка")
+		Flags().String("maxBudget", "1000", "Максимальный бюджет для одного подарка")
 	calculateCmd.
 		Flags().Int("maxCount", 10, "Максимальное количество позиций")
+	calculateCmd.
+		Flags().Float64("maxWeight", 0, "Максимальный вес подарка в кг (0 - без ограничения)")
+	calculateCmd.
+		Flags().String("strategy", domain.StrategyCatalogOrder, "Стратегия подбора (catalog_order, cheapest_first)")
+	calculateCmd.
+		Flags().String("regions", "", "Файл региональных коэффициентов, по умолчанию <data-dir>/regions.json")
+	calculateCmd.
+		Flags().String("allocation", domain.AllocationUnlimited, "Режим распределения (unlimited, stock)")
+	calculateCmd.
+		Flags().String("history", "", "Файл истории подарков прошлых лет")
+	calculateCmd.
+		Flags().String("rules", "", "Правила соответствия требованиям, по умолчанию <data-dir>/rules.json")
+	calculateCmd.
+		Flags().String("certifications", "", "Реестр сертификатов, по умолчанию <data-dir>/certifications.json")
+	calculateCmd.
+		Flags().String("rates", "", "Таблица курсов валют, по умолчанию <data-dir>/exchange-rates.json")
+	calculateCmd.
+		Flags().String("mode", domain.ModeIndividual, "Режим подбора (individual, template)")
+	calculateCmd.
+		Flags().Bool("includeDelivery", false, "Включать стоимость доставки в бюджет подарка")
+	calculateCmd.
+		Flags().Bool("explain", false, "Записать в отчет решение по каждому предмету-кандидату")
 }
 
 func runCalculate(cmd *cobra.Command, args []string) {
-	childrenDataFile, err := cmd.Flags().GetString("children")
+	settings, configPath, err := resolveSettings(cmd)
 	if err != nil {
+		logFileError(err)
 		return
 	}
 
-	if childrenDataFile == "" {
-		slog.Error("Необходимо передать данные о детях")
-		return
+	if configPath != "" {
+		slog.Info("Используется файл конфигурации", slog.String("config", configPath))
 	}
 
-	catalogDataFile, err := cmd.Flags().GetString("catalog")
+	in, err := loadInput(settings)
 	if err != nil {
+		logFileError(err)
 		return
 	}
 
-	reportFile, err := cmd.Flags().GetString("report")
+	explain, err := cmd.Flags().GetBool("explain")
 	if err != nil {
 		return
 	}
 
-	if catalogDataFile == "" {
-		slog.Error("Необходимо передать данные каталога")
-		return
-	}
+	report := calculation.Run(in, calculationOptions(settings, explain))
 
-	childrenBinData, err := os.ReadFile(childrenDataFile)
+	data, err := json.Marshal(report)
 	if err != nil {
-		slog.Error("Не могу найти файл '" + childrenDataFile + "'")
+		slog.Error("Не смог сформировать файл отчета", slog.String("err", err.Error()))
 		return
 	}
 
-	catalogBinData, err := os.ReadFile(catalogDataFile)
-	if err != nil {
-		slog.Error("Не могу найти файл '" + catalogDataFile + "'")
+	if err := os.WriteFile(settings.Report, data, 0644); err != nil {
+		slog.Error("Не смог записать файл отчета", slog.String("file", settings.Report), slog.String("err", err.Error()))
 		return
 	}
 
-	// TODO: получить maxBudget из аргументов
-
-	// TODO: получить maxCount из аргументов
+	slog.Info("Отчет сформирован",
+		slog.String("file", settings.Report),
+		slog.Int("children", len(report.Results)),
+	)
+}
 
-	childrenData := domain.ChildrenData{}
+// loadInput читает входные файлы расчета, указанные в настройках.
+func loadInput(settings config.Settings) (calculation.Input, error) {
+	if settings.Children == "" {
+		return calculation.Input{}, errors.New("необходимо передать данные о детях")
+	}
 
-	err = json.Unmarshal(childrenBinData, &childrenData)
-	if err != nil {
-		slog.Error("Не могу разобрать файл '"+childrenDataFile+"'", slog.String("err", err.Error()))
-		return
+	if settings.Catalog == "" {
+		return calculation.Input{}, errors.New("необходимо передать данные каталога")
 	}
 
-	maxCount := 10
-	maxBudget := 100.0
+	childrenData := domain.ChildrenData{}
+	if err := readDataFile(schema.KindChildren, settings.Children, &childrenData); err != nil {
+		return calculation.Input{}, err
+	}
 
 	catalog := domain.CatalogData{}
-	err = json.Unmarshal(catalogBinData, &catalog)
-
-	report := domain.Report{
-		Version:     "v1.0.0",
-		GeneratedAt: time.Now(),
+	if err := readDataFile(schema.KindCatalog, settings.Catalog, &catalog); err != nil {
+		return calculation.Input{}, err
 	}
 
-	report.Results = make([]domain.ChildResult, 0, len(childrenData.Children))
+	var wishes []domain.Wish
+	if settings.Wishes != "" {
+		if err := readDataFile(schema.KindWishes, settings.Wishes, &wishes); err != nil {
+			return calculation.Input{}, err
+		}
+	}
 
-	for _, child := range childrenData.Children {
-		giftSelection := make([]domain.GiftSelection, 0)
-		//TODO: написать код подбора подарка
-		giftSelection, price := selectGiftsForChild(child, catalog.Items, maxCount, maxBudget)
+	return prepareInput(settings, childrenData.Children, catalog.Items, wishes)
+}
 
-		report.Results = append(report.Results, domain.ChildResult{
-			ChildID:             child.ID,
-			ChildName:           child.Name,
-			Age:                 child.Age,
-			Region:              child.Region,
-			SpecialRequirements: child.SpecialRequirements,
-			GiftSelection:       giftSelection,
-			CostSummary: domain.ChildCostSummary{
-				Cost:       price,
-				ItemsCount: len(giftSelection),
-			},
-		})
+// prepareInput пересчитывает каталог в базовую валюту и подключает историю
+// подарков. Используется и для файлов, и для данных из запроса API.
+func prepareInput(settings config.Settings, children []domain.Child, catalog []domain.CatalogItem, wishes []domain.Wish) (calculation.Input, error) {
+	if settings.Mode == domain.ModeTemplate && len(settings.Templates) == 0 {
+		return calculation.Input{}, errors.New("для режима template необходимо описать шаблоны в файле конфигурации")
 	}
 
-	//TODO: статистику
-	report.AgeGroupAnalysis = domain.AgeGroupAnalysis{
-		AgeGroup:      "",
-		MinAge:        0,
-		MaxAge:        0,
-		ChildrenCount: len(childrenData.Children),
-		TotalCost:     0,
-		AverageCost:   0,
+	rates, err := loadExchangeRates(settings)
+	if err != nil {
+		return calculation.Input{}, err
 	}
-
-	data, err := json.Marshal(report)
+	used := domain.CatalogCurrencies(catalog, rates.Base)
+	items, err := rates.ConvertCatalog(catalog)
 	if err != nil {
-		slog.Error("Не смог сформировать файл отчета", slog.String("err", err.Error()))
-		return
+		return calculation.Input{}, fmt.Errorf("каталог: %w", err)
 	}
 
-	_ = os.WriteFile(reportFile, data, 0644)
-}
-
-// TODO: написать код подбора подарка
-// Нужно реализовать:
-func selectGiftsForChild(
-	child domain.Child,
-	catalog []domain.CatalogItem,
-	maxCount int,
-	maxBudget float64,
-) ([]domain.GiftSelection, float64) {
-	// Алгоритм:
-	price := 0.0
-	count := 0
-	res := make([]domain.GiftSelection, 0, maxCount)
-
-	customCatalog := catalog
-
-	if len(child.SpecialRequirements.Other) > 0 {
-		customCatalog = []domain.CatalogItem{}
-		for _, o := range child.SpecialRequirements.Other {
-			for _, c := range catalog {
-				switch o {
-				case domain.OtherEducational:
-					if c.Metadata.Educational {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				case domain.OtherBilingual:
-					if c.Metadata.Bilingual {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				case domain.OtherCharitySupported:
-					if c.Metadata.CharitySupported {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				case domain.OtherSustainable:
-					if c.Metadata.Durable {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				case domain.OtherEcoFriendly:
-					if c.Metadata.EcoFriendly {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				case domain.OtherGenderNeutral:
-					if c.Metadata.GenderNeutral {
-						customCatalog = append(customCatalog, c)
-						break
-					}
-				}
-			}
+	if settings.Mode == domain.ModeTemplate {
+		if err := domain.ValidateTemplateDefaults(settings.Templates, items); err != nil {
+			return calculation.Input{}, fmt.Errorf("шаблоны: %w", err)
 		}
 	}
 
-	if len(child.SpecialRequirements.Medical) > 0 {
-		cc := []domain.CatalogItem{}
-		for _, m := range child.SpecialRequirements.Medical {
-			for _, item := range customCatalog {
-				switch m {
-				case domain.MedicalAsthma:
-					if !item.Metadata.HasFuzzyMaterial {
-						cc = append(cc, item)
-					}
-				case domain.DietaryHalal:
-					if !item.Metadata.CharitySupported {
-						cc = append(cc, item)
-					}
-				case domain.MedicalAutismFriendly:
-					if !item.Metadata.Tactile {
-						cc = append(cc, item)
-					}
-
-				}
-			}
+	var history *domain.GiftHistory
+	if settings.History != nil && settings.HistoryFile != "" {
+		h, err := loadHistory(settings.HistoryFile)
+		if err != nil {
+			return calculation.Input{}, err
 		}
+		history = h
 	}
 
-	customCatalog = cc
-}
-
-for _, catalogItem := range customCatalog {
-// 1. Проверить возрастные ограничения
-if catalogItem.MinAge > child.Age {
-continue
+	return calculation.Input{
+		Children: children,
+		Catalog:  items,
+		Wishes:   wishes,
+		History:  history,
+		Currency: rates.ReportCurrency(used),
+	}, nil
 }
 
-// 2. Проверить специальные требования
-if child.SpecialRequirements != nil {
-
-}
+// loadExchangeRates читает таблицу курсов. Если файла нет, возвращается
+// пустая таблица базовой валюты: каталог должен быть целиком в ней.
+func loadExchangeRates(settings config.Settings) (*domain.ExchangeRates, error) {
+	base := firstNonEmpty(settings.BaseCurrency, domain.DefaultCurrency)
+	if settings.ExchangeRates == "" {
+		return &domain.ExchangeRates{Base: base}, nil
+	}
+	if _, err := os.Stat(settings.ExchangeRates); errors.Is(err, os.ErrNotExist) {
+		return &domain.ExchangeRates{Base: base}, nil
+	}
 
-// 4. Проверить бюджетные ограничения
-price += catalogItem.Price
-count++
-if count >= maxCount || price >= maxBudget {
-break
-}
+	rates := &domain.ExchangeRates{}
+	if err := readDataFile(schema.KindRates, settings.ExchangeRates, rates); err != nil {
+		return nil, err
+	}
+	if err := rates.Validate(); err != nil {
+		return nil, fmt.Errorf("файл '%s': %w", settings.ExchangeRates, err)
+	}
+	if rates.Base != base {
+		return nil, fmt.Errorf("файл '%s': базовая валюта %s не совпадает с валютой кампании %s",
+			settings.ExchangeRates, rates.Base, base)
+	}
+	return rates, nil
 }
 
-// 5. Вернуть результат выбранные подарки и сумму
-return res, price
+// calculationOptions переводит настройки в параметры расчета.
+func calculationOptions(settings config.Settings, explain bool) calculation.Options {
+	return calculation.Options{
+		MaxCount:     settings.MaxCount,
+		MaxBudget:    settings.MaxBudget,
+		MaxWeight:    settings.MaxWeight,
+		Strategy:     settings.Strategy,
+		Allocation:   settings.Allocation,
+		Coefficients: settings.Coefficients(),
+		Composition:  settings.Composition,
+		Mode:         settings.Mode,
+		Templates:    settings.Templates,
+		Household:    settings.Household,
+		History:      settings.History,
+		Severity:     settings.Severity,
+		SafetyPolicy: settings.SafetyPolicy,
+		Explain:      explain,
+
+		Delivery:        settings.Delivery,
+		DeliveryTariffs: settings.DeliveryTariffs(),
+		IncludeDelivery: settings.IncludeDelivery != nil && *settings.IncludeDelivery,
+		Boxes:           settings.Boxes,
+	}
 }
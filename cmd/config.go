@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Показать итоговые настройки с учетом профиля и переменных окружения",
+	Run:   runConfig,
+}
+
+func runConfig(cmd *cobra.Command, args []string) {
+	settings, configPath, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	if configPath == "" {
+		slog.Info("Файл конфигурации не найден, используются встроенные настройки")
+	} else {
+		slog.Info("Используется файл конфигурации", slog.String("config", configPath))
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		slog.Error("Не смог сформировать настройки", slog.String("err", err.Error()))
+		return
+	}
+
+	fmt.Println(string(data))
+}
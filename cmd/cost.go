@@ -1,7 +1,15 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/calculation"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
 
 	"github.com/spf13/cobra"
 )
@@ -9,9 +17,68 @@ import (
 var costCmd = &cobra.Command{
 	Use:   "cost",
 	Short: "Расчет стоимости подарков",
-	Run:   runCost,
+	Long: `Выводит стоимость подарков и их доставки по регионам из отчета.
+Доставка считается командой calculate, если в конфигурации задана модель доставки.`,
+	Run: runCost,
+}
+
+func init() {
+	costCmd.
+		Flags().String("report", "report.json", "Файл отчета")
 }
 
 func runCost(cmd *cobra.Command, args []string) {
-	slog.Info("Расчет стоимости")
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	report := domain.Report{}
+	if err := readDataFile(schema.KindReport, settings.Report, &report); err != nil {
+		logFileError(err)
+		return
+	}
+
+	regions := report.RegionAnalysis
+	if len(regions) == 0 {
+		slog.Debug("В отчете нет анализа по регионам, считаю по результатам",
+			slog.String("file", settings.Report),
+		)
+		regions = calculation.AnalyzeRegions(report.Results, settings.Coefficients())
+	}
+
+	var shared domain.Money
+	for _, h := range report.Households {
+		for _, item := range h.SharedItems {
+			shared += item.Price
+		}
+	}
+
+	renderCost(os.Stdout, regions, shared)
+}
+
+func renderCost(out io.Writer, regions []domain.RegionAnalysis, shared domain.Money) {
+	var total domain.RegionAnalysis
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Регион\tКоэфф.\tДетей\tПодарки\tСредний\tВес, кг\tДоставка\tИтого\t")
+	for _, r := range regions {
+		fmt.Fprintf(w, "%s\t%.2f\t%d\t%s\t%s\t%.2f\t%s\t%s\t\n",
+			r.Region, r.Coefficient, r.ChildrenCount, r.TotalCost, r.AverageCost,
+			r.TotalWeight, r.DeliveryCost, r.TotalCost+r.DeliveryCost)
+
+		total.ChildrenCount += r.ChildrenCount
+		total.TotalCost += r.TotalCost
+		total.TotalWeight += r.TotalWeight
+		total.DeliveryCost += r.DeliveryCost
+	}
+	fmt.Fprintf(w, "Всего\t\t%d\t%s\t%s\t%.2f\t%s\t%s\t\n",
+		total.ChildrenCount, total.TotalCost, total.TotalCost.Div(total.ChildrenCount),
+		total.TotalWeight, total.DeliveryCost, total.TotalCost+total.DeliveryCost)
+	w.Flush()
+
+	if shared > 0 {
+		fmt.Fprintf(out, "\nВ том числе общие подарки семей: %s\n", shared)
+	}
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/comparison"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-report.json> <new-report.json>",
+	Short: "Сравнить два отчета",
+	Long: `Сравнивает подарки детей в двух отчетах по ID ребенка: добавленные и убранные
+предметы, изменение стоимости, дети, для которых подарок перестал или начал
+подбираться, а также итоги по регионам и возрастным группам.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDiff,
+}
+
+func init() {
+	diffCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	var oldReport, newReport domain.Report
+	if err := readDataFile(schema.KindReport, args[0], &oldReport); err != nil {
+		logFileError(err)
+		return
+	}
+	if err := readDataFile(schema.KindReport, args[1], &newReport); err != nil {
+		logFileError(err)
+		return
+	}
+
+	diff := comparison.Compare(oldReport, newReport)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать результат сравнения", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderDiff(os.Stdout, diff)
+}
+
+func renderDiff(out io.Writer, diff comparison.ReportDiff) {
+	fmt.Fprintf(out, "Итого: %s -> %s (%+.2f)\n", diff.OldTotal, diff.NewTotal, diff.CostDelta.Float())
+	if len(diff.AddedChildren) > 0 {
+		fmt.Fprintf(out, "Новые дети: %v\n", diff.AddedChildren)
+	}
+	if len(diff.RemovedChildren) > 0 {
+		fmt.Fprintf(out, "Убраны дети: %v\n", diff.RemovedChildren)
+	}
+	if len(diff.StartedFailing) > 0 {
+		fmt.Fprintf(out, "Подарок перестал подбираться: %v\n", diff.StartedFailing)
+	}
+	if len(diff.StoppedFailing) > 0 {
+		fmt.Fprintf(out, "Подарок начал подбираться: %v\n", diff.StoppedFailing)
+	}
+	fmt.Fprintln(out)
+
+	if len(diff.Children) == 0 {
+		fmt.Fprintln(out, "Подарки детей не изменились")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tРебенок\tСтоимость\tИзменение\tДобавлено\tУбрано")
+		for _, c := range diff.Children {
+			fmt.Fprintf(w, "%d\t%s\t%s -> %s\t%+.2f\t%s\t%s\n",
+				c.ChildID, c.ChildName, c.OldCost, c.NewCost, c.CostDelta.Float(),
+				itemNames(c.AddedItems), itemNames(c.RemovedItems))
+		}
+		w.Flush()
+	}
+
+	renderGroupDeltas(out, "Регион", diff.Regions)
+	renderGroupDeltas(out, "Возрастная группа", diff.AgeGroups)
+}
+
+func renderGroupDeltas(out io.Writer, title string, deltas []comparison.GroupDelta) {
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tДетей\tБыло\tСтало\tИзменение\n", title)
+	for _, d := range deltas {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%+.2f\n", d.Name, d.Children, d.OldCost, d.NewCost, d.CostDelta.Float())
+	}
+	w.Flush()
+}
+
+func itemNames(items []comparison.ItemRef) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, fmt.Sprintf("%d %s", item.ItemID, item.ItemName))
+	}
+	return strings.Join(names, "; ")
+}
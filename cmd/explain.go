@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Показать, почему ребенку подобраны (или не подобраны) предметы",
+	Long: `Выводит решение по каждому предмету-кандидату для ребенка.
+Отчет должен быть сформирован командой calculate с флагом --explain.`,
+	Run: runExplain,
+}
+
+func init() {
+	explainCmd.
+		Flags().String("report", "report.json", "Файл отчета")
+	explainCmd.
+		Flags().Int("child", 0, "ID ребенка")
+}
+
+var outcomeTitles = map[domain.SelectionOutcome]string{
+	domain.OutcomeAccepted:            "принят",
+	domain.OutcomeRejectedAge:         "отклонен по возрасту",
+	domain.OutcomeRejectedRequirement: "отклонен по требованию",
+	domain.OutcomeRejectedComposition: "отклонен правилом состава",
+	domain.OutcomeRejectedHistory:     "отклонен: уже дарили",
+	domain.OutcomeSkippedBudget:       "пропущен: бюджет",
+	domain.OutcomeSkippedCount:        "пропущен: количество",
+	domain.OutcomeSkippedWeight:       "пропущен: вес",
+	domain.OutcomeSkippedStock:        "пропущен: нет на складе",
+}
+
+func runExplain(cmd *cobra.Command, args []string) {
+	reportFile, err := cmd.Flags().GetString("report")
+	if err != nil {
+		return
+	}
+
+	childID, err := cmd.Flags().GetInt("child")
+	if err != nil {
+		return
+	}
+
+	if childID <= 0 {
+		slog.Error("Необходимо передать ID ребенка")
+		return
+	}
+
+	report := domain.Report{}
+	if err := readDataFile(schema.KindReport, reportFile, &report); err != nil {
+		logFileError(err)
+		return
+	}
+
+	var result *domain.ChildResult
+	for i := range report.Results {
+		if report.Results[i].ChildID == childID {
+			result = &report.Results[i]
+			break
+		}
+	}
+
+	if result == nil {
+		slog.Error("Ребенок не найден в отчете", slog.Int("child_id", childID))
+		return
+	}
+
+	if len(result.SelectionTrace) == 0 {
+		slog.Error("В отчете нет трассировки подбора, пересчитайте с флагом --explain")
+		return
+	}
+
+	renderExplanation(os.Stdout, result)
+}
+
+func renderExplanation(out io.Writer, result *domain.ChildResult) {
+	fmt.Fprintf(out, "Ребенок #%d %s, %d лет, %s\n", result.ChildID, result.ChildName, result.Age, result.Region)
+	fmt.Fprintf(out, "Требования: %s\n", result.SpecialRequirements.String())
+	if len(result.PolicyRequirements) > 0 {
+		names := make([]string, len(result.PolicyRequirements))
+		for i, req := range result.PolicyRequirements {
+			names[i] = string(req)
+		}
+		fmt.Fprintf(out, "Добавлено политикой: %s\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintf(out, "Подарок: %d позиций на сумму %s\n", result.CostSummary.ItemsCount, result.CostSummary.Cost)
+	for _, note := range result.SelectionNotes {
+		fmt.Fprintf(out, "Примечание: %s\n", note)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tПредмет\tРешение\tПричина")
+	for _, d := range result.SelectionTrace {
+		reason := d.Reason
+		if len(d.Requirements) > 0 {
+			reason = strings.Join(d.Requirements, ", ") + ": " + reason
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", d.ItemID, d.ItemName, outcomeTitle(d.Outcome), reason)
+	}
+	w.Flush()
+}
+
+func outcomeTitle(outcome domain.SelectionOutcome) string {
+	if title, ok := outcomeTitles[outcome]; ok {
+		return title
+	}
+	return string(outcome)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Работа с историей подарков прошлых лет",
+}
+
+var historyImportCmd = &cobra.Command{
+	Use:   "import <report.json>",
+	Short: "Добавить в историю подарки из отчета прошлого года",
+	Long: `Читает отчет и записывает подарки каждого ребенка в файл истории.
+Повторный импорт того же года заменяет ранее загруженные записи.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHistoryImport,
+}
+
+func init() {
+	historyCmd.
+		PersistentFlags().String("history", "", "Файл истории подарков, по умолчанию <data-dir>/history.json")
+	historyImportCmd.
+		Flags().Int("year", 0, "Год выдачи подарков (по умолчанию год формирования отчета)")
+
+	historyCmd.AddCommand(historyImportCmd)
+}
+
+func runHistoryImport(cmd *cobra.Command, args []string) {
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	year, err := cmd.Flags().GetInt("year")
+	if err != nil {
+		return
+	}
+
+	report := domain.Report{}
+	if err := readDataFile(schema.KindReport, args[0], &report); err != nil {
+		logFileError(err)
+		return
+	}
+
+	if year <= 0 {
+		year = report.GeneratedAt.Year()
+	}
+
+	history, err := loadHistory(settings.HistoryFile)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	added := history.Import(year, report)
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		slog.Error("Не смог сформировать файл истории", slog.String("err", err.Error()))
+		return
+	}
+
+	if err := os.WriteFile(settings.HistoryFile, data, 0644); err != nil {
+		slog.Error("Не смог записать файл истории", slog.String("file", settings.HistoryFile), slog.String("err", err.Error()))
+		return
+	}
+
+	slog.Info("История подарков обновлена",
+		slog.String("file", settings.HistoryFile),
+		slog.Int("year", year),
+		slog.Int("records", added),
+		slog.Any("years", history.Years()),
+	)
+}
+
+// loadHistory читает файл истории подарков. Отсутствующий файл означает пустую историю.
+func loadHistory(path string) (*domain.GiftHistory, error) {
+	history := &domain.GiftHistory{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+
+	if err := readDataFile(schema.KindHistory, path, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/comparison"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact",
+	Short: "Оценить влияние изменения каталога на подарки и бюджет",
+	Long: `Сравнивает две версии каталога: изменения цен и метаданных, предметы,
+переставшие соответствовать требованиям детей (например, contains_nuts при
+nuts_allergy), изменения подарков и общий эффект для бюджета.`,
+	Run: runImpact,
+}
+
+func init() {
+	impactCmd.
+		Flags().String("catalog-old", "", "Текущая версия каталога")
+	impactCmd.
+		Flags().String("catalog-new", "", "Новая версия каталога")
+	impactCmd.
+		Flags().String("children", "", "Файл с данными о детях (JSON), по умолчанию <data-dir>/children.json")
+	impactCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
+}
+
+func runImpact(cmd *cobra.Command, args []string) {
+	oldFile, err := cmd.Flags().GetString("catalog-old")
+	if err != nil {
+		return
+	}
+
+	newFile, err := cmd.Flags().GetString("catalog-new")
+	if err != nil {
+		return
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if oldFile == "" || newFile == "" {
+		slog.Error("Необходимо передать обе версии каталога")
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+	settings.Catalog = oldFile
+
+	in, err := loadInput(settings)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	newCatalog := domain.CatalogData{}
+	if err := readDataFile(schema.KindCatalog, newFile, &newCatalog); err != nil {
+		logFileError(err)
+		return
+	}
+
+	rates, err := loadExchangeRates(settings)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+	newItems, err := rates.ConvertCatalog(newCatalog.Items)
+	if err != nil {
+		slog.Error("Не могу пересчитать цены нового каталога", slog.String("file", newFile), slog.String("err", err.Error()))
+		return
+	}
+
+	impact := comparison.Impact(in, newItems, calculationOptions(settings, false))
+
+	if format == "json" {
+		data, err := json.MarshalIndent(impact, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать результат анализа", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderImpact(os.Stdout, impact)
+}
+
+var itemStatusTitles = map[string]string{
+	comparison.ItemAdded:   "добавлен",
+	comparison.ItemRemoved: "удален",
+	comparison.ItemChanged: "изменен",
+}
+
+func renderImpact(out io.Writer, impact comparison.CatalogImpact) {
+	if len(impact.Items) == 0 {
+		fmt.Fprintln(out, "Каталог не изменился")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tПредмет\tСтатус\tЦена\tИзменение\tМетаданные")
+		for _, c := range impact.Items {
+			fields := make([]string, 0, len(c.MetadataChanges))
+			for _, f := range c.MetadataChanges {
+				fields = append(fields, f.String())
+			}
+			meta := "-"
+			if len(fields) > 0 {
+				meta = strings.Join(fields, "; ")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s -> %s\t%+.2f\t%s\n",
+				c.ItemID, c.ItemName, itemStatusTitles[c.Status], c.OldPrice, c.NewPrice, c.PriceDelta.Float(), meta)
+		}
+		w.Flush()
+	}
+
+	if len(impact.SafetyAlerts) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "ВНИМАНИЕ: предметы перестали соответствовать требованиям")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tПредмет\tТребование\tДети")
+		for _, a := range impact.SafetyAlerts {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%v\n", a.ItemID, a.ItemName, a.Requirement, a.ChildIDs)
+		}
+		w.Flush()
+	}
+
+	fmt.Fprintln(out)
+	renderDiff(out, impact.Selection)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"giftcalc/internal/infrastructure/schema"
+)
+
+// invalidFileError - файл не прошел проверку по схеме.
+type invalidFileError struct {
+	file       string
+	violations []schema.ValidationError
+}
+
+func (e *invalidFileError) Error() string {
+	return fmt.Sprintf("файл '%s' не соответствует схеме: нарушений %d", e.file, len(e.violations))
+}
+
+// readDataFile читает файл данных, проверяет его по схеме и декодирует в v.
+func readDataFile(kind schema.Kind, path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не могу найти файл '%s': %w", path, err)
+	}
+
+	if err := checkSchema(kind, path, data); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("не могу разобрать файл '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// checkSchema проверяет содержимое файла по схеме указанного вида.
+func checkSchema(kind schema.Kind, path string, data []byte) error {
+	violations, err := schema.ValidateDocument(kind, data)
+	if err != nil {
+		return fmt.Errorf("файл '%s': %w", path, err)
+	}
+	if len(violations) > 0 {
+		return &invalidFileError{file: path, violations: violations}
+	}
+	return nil
+}
+
+// logFileError выводит ошибку чтения файла, раскрывая каждое нарушение схемы.
+func logFileError(err error) {
+	var invalid *invalidFileError
+	if !errors.As(err, &invalid) {
+		slog.Error(err.Error())
+		return
+	}
+
+	for _, v := range invalid.violations {
+		slog.Error(v.Message,
+			slog.String("file", invalid.file),
+			slog.Int("line", v.Line),
+			slog.Int("column", v.Column),
+			slog.String("pointer", v.Pointer),
+		)
+	}
+	slog.Error(invalid.Error())
+}
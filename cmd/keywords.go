@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var keywordsCmd = &cobra.Command{
+	Use:   "keywords",
+	Short: "Показать, какие предметы каталога совпадают с наборами ключевых слов",
+	Long: `Для каждого набора ключевых слов (свинина, халяль, гарантия и т.д.) выводит
+предметы каталога, в названии, категории, материалах, сертификатах или
+предупреждениях которых найдены слова набора, и сами найденные слова.`,
+	Run: runKeywords,
+}
+
+func init() {
+	keywordsCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	keywordsCmd.
+		Flags().String("keywords", "", "Словарь ключевых слов, по умолчанию <data-dir>/keywords.json")
+	keywordsCmd.
+		Flags().String("set", "", "Показать только указанный набор")
+}
+
+func runKeywords(cmd *cobra.Command, args []string) {
+	set, err := cmd.Flags().GetString("set")
+	if err != nil {
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	sets := domain.KeywordSets()
+	if set != "" {
+		if !slices.Contains(sets, set) {
+			slog.Error("Неизвестный набор ключевых слов",
+				slog.String("set", set),
+				slog.String("available", strings.Join(sets, ", ")))
+			return
+		}
+		sets = []string{set}
+	}
+
+	catalog := domain.CatalogData{}
+	if err := readDataFile(schema.KindCatalog, settings.Catalog, &catalog); err != nil {
+		logFileError(err)
+		return
+	}
+
+	renderKeywordMatches(os.Stdout, sets, catalog.Items)
+}
+
+func renderKeywordMatches(out io.Writer, sets []string, items []domain.CatalogItem) {
+	fmt.Fprintf(out, "Словарь: %s\n\n", domain.KeywordsVersion())
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Набор\tID\tПредмет\tНайдено")
+	for _, set := range sets {
+		found := false
+		for _, item := range items {
+			texts := append([]string{item.Name, item.Category}, item.Metadata.Materials...)
+			texts = append(texts, item.Metadata.Certifications...)
+			texts = append(texts, item.Metadata.Warnings...)
+
+			matched := slices.Compact(slices.Sorted(slices.Values(domain.MatchKeywords(set, texts...))))
+			if len(matched) == 0 {
+				continue
+			}
+			found = true
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", set, item.Id, item.Name, strings.Join(matched, ", "))
+		}
+		if !found {
+			fmt.Fprintf(w, "%s\t-\t-\t-\n", set)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/logistics"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/config"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var logisticsCmd = &cobra.Command{
+	Use:   "logistics",
+	Short: "План загрузки саней и грузовиков по регионам",
+	Long: `Раскладывает готовые подарки из отчета по рейсам с учетом вместимости
+по весу и объему. Регионы объезжаются в порядке маршрута из файла регионов
+(поле order, координаты location); рейсы не смешивают регионы.
+Объем предметов берется из каталога (поле volume).`,
+	Run: runLogistics,
+}
+
+func init() {
+	logisticsCmd.
+		Flags().String("report", "report.json", "Файл отчета")
+	logisticsCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	logisticsCmd.
+		Flags().String("regions", "", "Файл регионов с координатами и порядком маршрута, по умолчанию <data-dir>/regions.json")
+	logisticsCmd.
+		Flags().Float64("loadWeight", 0, "Вместимость рейса по весу, кг")
+	logisticsCmd.
+		Flags().Float64("loadVolume", 0, "Вместимость рейса по объему, л")
+	logisticsCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
+}
+
+func runLogistics(cmd *cobra.Command, args []string) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	manifest, _, _, err := planLoads(cmd, settings)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать план загрузки", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderManifest(os.Stdout, manifest)
+}
+
+// planLoads читает отчет и каталог и строит план загрузки. Вместимость
+// рейса из конфигурации перекрывается флагами --loadWeight и --loadVolume.
+func planLoads(cmd *cobra.Command, settings config.Settings) (domain.LoadManifest, domain.LogisticsRules, domain.Report, error) {
+	rules := domain.DefaultLogisticsRules
+	if settings.Logistics != nil {
+		rules = *settings.Logistics
+	}
+	if v, _ := cmd.Flags().GetFloat64("loadWeight"); v > 0 {
+		rules.Vehicle.MaxWeight = v
+	}
+	if v, _ := cmd.Flags().GetFloat64("loadVolume"); v > 0 {
+		rules.Vehicle.MaxVolume = v
+	}
+	if err := rules.Validate(); err != nil {
+		return domain.LoadManifest{}, rules, domain.Report{}, fmt.Errorf("параметры загрузки: %w", err)
+	}
+
+	report := domain.Report{}
+	if err := readDataFile(schema.KindReport, settings.Report, &report); err != nil {
+		return domain.LoadManifest{}, rules, report, err
+	}
+
+	catalog := domain.CatalogData{}
+	if err := readDataFile(schema.KindCatalog, settings.Catalog, &catalog); err != nil {
+		return domain.LoadManifest{}, rules, report, err
+	}
+
+	manifest := logistics.Plan(report.Results, catalog.Items, settings.Regions, settings.Boxes, rules)
+	if len(manifest.Oversized) > 0 {
+		slog.Warn("Коробки не помещаются в рейс", slog.Int("count", len(manifest.Oversized)))
+	}
+	return manifest, rules, report, nil
+}
+
+func renderManifest(out io.Writer, manifest domain.LoadManifest) {
+	vehicle := firstNonEmpty(manifest.Vehicle.Name, "рейс")
+	fmt.Fprintf(out, "Вместимость (%s): %.2f кг, %.2f л\n", vehicle, manifest.Vehicle.MaxWeight, manifest.Vehicle.MaxVolume)
+
+	fmt.Fprintln(out, "\nМаршрут:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, stop := range manifest.Route {
+		fmt.Fprintf(w, "  %d.\t%s\t%.0f км\tрейсов: %d\n", stop.Order, stop.Region, stop.Distance, stop.Loads)
+	}
+	w.Flush()
+	if manifest.Distance > 0 {
+		fmt.Fprintf(out, "  Всего: %.0f км\n", manifest.Distance)
+	}
+
+	for _, load := range manifest.Loads {
+		fmt.Fprintf(out, "\nРейс %d, %s: %.2f кг (%.0f%%), %.2f л (%.0f%%)\n",
+			load.Number, load.Region,
+			load.Weight, load.WeightUtilization*100,
+			load.Volume, load.VolumeUtilization*100)
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, box := range load.Boxes {
+			fmt.Fprintf(w, "  #%d\t%s\t%.2f кг\t%.2f л\n", box.ChildID, box.ChildName, box.Weight, box.Volume)
+		}
+		w.Flush()
+	}
+
+	if len(manifest.Oversized) > 0 {
+		fmt.Fprintln(out, "\nНе помещаются в рейс:")
+		for _, box := range manifest.Oversized {
+			fmt.Fprintf(out, "  #%d %s (%s): %.2f кг, %.2f л\n", box.ChildID, box.ChildName, box.Region, box.Weight, box.Volume)
+		}
+	}
+}
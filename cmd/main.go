@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+
+	// Часовые пояса регионов не зависят от базы tzdata системы.
+	_ "time/tzdata"
 )
 
 func main() {
@@ -11,6 +14,19 @@ func main() {
 		calculateCmd,
 		costCmd,
 		productionCmd,
+		schemaCmd,
+		validateCmd,
+		configCmd,
+		explainCmd,
+		historyCmd,
+		diffCmd,
+		scenarioCmd,
+		impactCmd,
+		notesCmd,
+		keywordsCmd,
+		logisticsCmd,
+		scheduleCmd,
+		serveCmd,
 	)
 
 	if err := rootCmd.Execute(); err != nil {
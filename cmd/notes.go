@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/notes"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Работа с заметками о детях",
+}
+
+var notesReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Предложить требования и запреты по заметкам о детях",
+	Long: `Разбирает заметки о детях по словарю и выводит предложения для проверки:
+дополнения к специальным требованиям и запреты предметов с оценкой уверенности.
+Предложения не применяются автоматически - их нужно перенести в файл детей вручную.`,
+	Run: runNotesReview,
+}
+
+func init() {
+	notesReviewCmd.
+		Flags().String("children", "", "Файл с данными о детях (JSON), по умолчанию <data-dir>/children.json")
+	notesReviewCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	notesReviewCmd.
+		Flags().String("dictionary", "", "Словарь разбора заметок, по умолчанию <data-dir>/notes-dictionary.json")
+	notesReviewCmd.
+		Flags().Float64("minConfidence", 0, "Не показывать предложения с меньшей уверенностью")
+	notesReviewCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
+
+	notesCmd.AddCommand(notesReviewCmd)
+}
+
+func runNotesReview(cmd *cobra.Command, args []string) {
+	minConfidence, err := cmd.Flags().GetFloat64("minConfidence")
+	if err != nil {
+		return
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	dict := domain.NotesDictionary{}
+	if err := readDataFile(schema.KindNotes, settings.NotesDictionary, &dict); err != nil {
+		logFileError(err)
+		return
+	}
+	if err := dict.Validate(); err != nil {
+		slog.Error("Некорректный словарь заметок", slog.String("file", settings.NotesDictionary), slog.String("err", err.Error()))
+		return
+	}
+
+	childrenData := domain.ChildrenData{}
+	if err := readDataFile(schema.KindChildren, settings.Children, &childrenData); err != nil {
+		logFileError(err)
+		return
+	}
+
+	catalog := domain.CatalogData{}
+	if err := readDataFile(schema.KindCatalog, settings.Catalog, &catalog); err != nil {
+		logFileError(err)
+		return
+	}
+
+	var proposals []notes.Proposal
+	for _, child := range childrenData.Children {
+		for _, p := range notes.Extract(child, &dict, catalog.Items) {
+			if p.Confidence >= minConfidence {
+				proposals = append(proposals, p)
+			}
+		}
+	}
+
+	slog.Debug("Заметки разобраны",
+		slog.String("dictionary", dict.Version),
+		slog.Int("children", len(childrenData.Children)),
+		slog.Int("proposals", len(proposals)),
+	)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(proposals, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать предложения", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderProposals(os.Stdout, proposals)
+}
+
+func renderProposals(out io.Writer, proposals []notes.Proposal) {
+	if len(proposals) == 0 {
+		fmt.Fprintln(out, "Предложений по заметкам нет")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tРебенок\tФрагмент\tПредложение\tУверенность\t")
+	for _, p := range proposals {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.2f\t\n", p.ChildID, p.ChildName, p.Fragment, proposalTitle(p), p.Confidence)
+	}
+	w.Flush()
+}
+
+func proposalTitle(p notes.Proposal) string {
+	switch {
+	case p.Category != "" && p.Existing:
+		return fmt.Sprintf("%s: %s (уже указано)", p.Category, p.Value)
+	case p.Category != "":
+		return fmt.Sprintf("добавить %s: %s", p.Category, p.Value)
+	case p.BanCategory != "":
+		return fmt.Sprintf("запретить категорию %s, предметы %v", p.BanCategory, p.ItemIDs)
+	default:
+		return fmt.Sprintf("запретить %q, предметы %v", p.Ban, p.ItemIDs)
+	}
+}
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/production"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
 
 	"github.com/spf13/cobra"
 )
@@ -9,9 +18,101 @@ import (
 var productionCmd = &cobra.Command{
 	Use:   "production",
 	Short: "Генерация производственного плана",
-	Run:   runProduction,
+	Long: `Составляет производственный план по отчету: количество предметов
+каждого вида, разбивку по категориям и количество коробок каждого размера,
+чтобы упаковку можно было заказать заранее.`,
+	Run: runProduction,
+}
+
+func init() {
+	productionCmd.
+		Flags().String("report", "report.json", "Файл отчета")
+	productionCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	productionCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
 }
 
 func runProduction(cmd *cobra.Command, args []string) {
-	slog.Info("Генерация производственного плана")
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	report := domain.Report{}
+	if err := readDataFile(schema.KindReport, settings.Report, &report); err != nil {
+		logFileError(err)
+		return
+	}
+
+	catalog := domain.CatalogData{}
+	if err := readDataFile(schema.KindCatalog, settings.Catalog, &catalog); err != nil {
+		logFileError(err)
+		return
+	}
+
+	categories := make(map[string]string, len(catalog.Categories))
+	for _, c := range catalog.Categories {
+		categories[c.Id] = c.Name
+	}
+
+	plan := production.Plan(report, categories, settings.Boxes)
+	if len(plan.Unboxed) > 0 {
+		slog.Warn("Для подарков не подобрана коробка", slog.Any("child_ids", plan.Unboxed))
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать производственный план", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderProduction(os.Stdout, plan)
+}
+
+func renderProduction(out io.Writer, plan domain.ProductionSummary) {
+	fmt.Fprintf(out, "Всего предметов: %d\n\n", plan.TotalItemsNeeded)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tПредмет\tКатегория\tКол-во\tСтоимость\tВес, кг\t")
+	for _, item := range plan.ItemsBreakdown {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%.2f\t\n",
+			item.ItemID, item.ItemName, item.Category, item.RequiredQuantity, item.TotalCost, item.TotalWeight)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nПо категориям:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range plan.CategoriesBreakdown {
+		fmt.Fprintf(w, "  %s\t%s\tвидов: %d\tкол-во: %d\t%s\t\n",
+			c.CategoryID, c.CategoryName, c.ItemsCount, c.TotalQuantity, c.TotalCost)
+	}
+	w.Flush()
+
+	if len(plan.BoxesBreakdown) > 0 || len(plan.Unboxed) > 0 {
+		fmt.Fprintln(out, "\nКоробки:")
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, b := range plan.BoxesBreakdown {
+			fmt.Fprintf(w, "  %s\t%s см\t%d шт.\t\n", b.Box, b.Dimensions, b.Quantity)
+		}
+		w.Flush()
+		if len(plan.Unboxed) > 0 {
+			fmt.Fprintf(out, "  Без стандартной коробки: %d (дети %v)\n", len(plan.Unboxed), plan.Unboxed)
+		}
+	}
 }
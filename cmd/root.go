@@ -12,21 +12,50 @@ import (
 )
 
 var (
-	dataDir   string
-	verbose   bool
-	logLevel  string
-	logFormat string // "json" или "console"
-	logToFile string
+	dataDir    string
+	verbose    bool
+	logLevel   string
+	logFormat  string // "json" или "console"
+	logToFile  string
+	configFile string
+	profile    string
+
+	logMaxSize    int
+	logMaxBackups int
 )
 
+// ownSignalsAnnotation - команда сама обрабатывает SIGINT/SIGTERM.
+const ownSignalsAnnotation = "giftcalc/own-signals"
+
 var rootCmd = &cobra.Command{
 	Use:   "giftcalc",
 	Short: "Система расчета новогодних подарков",
 	Long: `GiftCalc CLI - инструмент Аналитического отдела Деда Мороза
 для расчета стоимости и комплектации индивидуальных подарков для детей.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Обработка сигналов для graceful shutdown
-		setupSignalHandling()
+		// Единый конвейер логирования: slog -> zap
+		if err := logger.Init(logger.Options{
+			Level:      logLevel,
+			Format:     logFormat,
+			File:       logToFile,
+			Verbose:    verbose,
+			MaxSizeMB:  logMaxSize,
+			MaxBackups: logMaxBackups,
+		}); err != nil {
+			slog.Error("Не смог инициализировать логгер", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+
+		slog.Debug("Запуск команды",
+			slog.String("command", cmd.CommandPath()),
+			slog.Any("args", args),
+		)
+
+		// Обработка сигналов для graceful shutdown; команды с аннотацией
+		// ownSignalsAnnotation завершаются сами (например, serve).
+		if cmd.Annotations[ownSignalsAnnotation] == "" {
+			setupSignalHandling()
+		}
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		// Синхронизация логгера при завершении
@@ -46,7 +75,8 @@ func Execute() {
 }
 
 func init() {
-	// Глобальные флаги
+	// Глобальные флаги. Они пишутся через дефис, как исходные data-dir и log-*;
+	// флаги отдельных команд - в camelCase (maxBudget, includeDelivery).
 	rootCmd.PersistentFlags().StringVarP(&dataDir, "data-dir", "d", "./data", "Каталог с данными")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Подробный вывод")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
@@ -54,7 +84,15 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "console",
 		"Формат логов (json, console)")
 	rootCmd.PersistentFlags().StringVar(&logToFile, "log-file", "",
-		"Файл для записи логов (если не указан - stdout)")
+		"Файл для записи логов (если не указан - stderr)")
+	rootCmd.PersistentFlags().IntVar(&logMaxSize, "log-max-size", 100,
+		"Размер файла логов в МБ, после которого выполняется ротация")
+	rootCmd.PersistentFlags().IntVar(&logMaxBackups, "log-max-backups", 5,
+		"Количество хранимых ротированных файлов логов")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "",
+		"Файл конфигурации (по умолчанию giftcalc.json в каталоге данных)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"Профиль конфигурации (например test, 2025-final)")
 }
 
 func setupSignalHandling() {
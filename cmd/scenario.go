@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"giftcalc/internal/application/scenario"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Сравнить сценарии \"что если\" по сетке бюджетов, количества позиций и коэффициентов",
+	Long: `Выполняет расчет для каждого сочетания параметров из файла сценариев
+и выводит сравнительную таблицу: общая стоимость, полностью обслуженные дети,
+неудачные расчеты и среднее количество позиций в подарке.`,
+	Run: runScenario,
+}
+
+func init() {
+	scenarioCmd.
+		Flags().String("scenario", "", "Файл сетки сценариев (JSON)")
+	scenarioCmd.
+		Flags().String("children", "", "Файл с данными о детях (JSON), по умолчанию <data-dir>/children.json")
+	scenarioCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	scenarioCmd.
+		Flags().String("wishes", "", "Файл с пожеланиями детей")
+	scenarioCmd.
+		Flags().String("csv", "", "Записать таблицу сценариев в CSV файл")
+	scenarioCmd.
+		Flags().String("html", "", "Записать HTML страницу с диаграммой сценариев")
+}
+
+func runScenario(cmd *cobra.Command, args []string) {
+	scenarioFile, err := cmd.Flags().GetString("scenario")
+	if err != nil {
+		return
+	}
+
+	if scenarioFile == "" {
+		slog.Error("Необходимо передать файл сценариев")
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	grid := domain.ScenarioGrid{}
+	if err := readDataFile(schema.KindScenario, scenarioFile, &grid); err != nil {
+		logFileError(err)
+		return
+	}
+
+	in, err := loadInput(settings)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	outcomes := scenario.Run(in, calculationOptions(settings, false), grid)
+	renderScenarios(os.Stdout, outcomes)
+
+	if path, _ := cmd.Flags().GetString("csv"); path != "" {
+		if err := writeScenarioFile(path, outcomes, writeScenarioCSV); err != nil {
+			slog.Error("Не смог записать CSV", slog.String("file", path), slog.String("err", err.Error()))
+			return
+		}
+		slog.Info("Таблица сценариев записана", slog.String("file", path))
+	}
+
+	if path, _ := cmd.Flags().GetString("html"); path != "" {
+		if err := writeScenarioFile(path, outcomes, writeScenarioHTML); err != nil {
+			slog.Error("Не смог записать HTML", slog.String("file", path), slog.String("err", err.Error()))
+			return
+		}
+		slog.Info("Диаграмма сценариев записана", slog.String("file", path))
+	}
+}
+
+func renderScenarios(out io.Writer, outcomes []scenario.Outcome) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Бюджет\tПозиций\tКоэффициенты\tСтоимость\tОбслужено\tНе подобрано\tСредн. позиций\t")
+	for _, o := range outcomes {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d/%d\t%d\t%.2f\t\n",
+			o.MaxBudget, o.MaxCount, o.Coefficients, o.TotalCost,
+			o.FullyServed, o.ChildrenCount, o.Failed, o.AverageItems)
+	}
+	w.Flush()
+}
+
+func writeScenarioFile(path string, outcomes []scenario.Outcome, write func(io.Writer, []scenario.Outcome) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f, outcomes); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeScenarioCSV(out io.Writer, outcomes []scenario.Outcome) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"max_budget", "max_count", "coefficients", "total_cost", "fully_served", "failed", "average_items", "children"})
+	for _, o := range outcomes {
+		w.Write([]string{
+			o.MaxBudget.String(),
+			strconv.Itoa(o.MaxCount),
+			o.Coefficients,
+			o.TotalCost.String(),
+			strconv.Itoa(o.FullyServed),
+			strconv.Itoa(o.Failed),
+			strconv.FormatFloat(o.AverageItems, 'f', 2, 64),
+			strconv.Itoa(o.ChildrenCount),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+var scenarioPage = template.Must(template.New("scenario").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Сценарии расчета подарков</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-top: 2em; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+.bar { fill: #3b7dd8; }
+.served { fill: #4caf50; }
+text { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Сценарии расчета подарков</h1>
+<svg width="{{.Width}}" height="{{.Height}}">
+{{- range .Bars}}
+<text x="0" y="{{.Y}}" dy="14">{{.Label}}</text>
+<rect class="bar" x="{{$.LabelWidth}}" y="{{.Y}}" width="{{.CostWidth}}" height="9"></rect>
+<rect class="served" x="{{$.LabelWidth}}" y="{{.ServedY}}" width="{{.ServedWidth}}" height="9"></rect>
+<text x="{{.TextX}}" y="{{.Y}}" dy="9">{{.Outcome.TotalCost}} / {{.Outcome.FullyServed}} детей</text>
+{{- end}}
+</svg>
+<p><svg width="12" height="12"><rect class="bar" width="12" height="12"></rect></svg> стоимость
+<svg width="12" height="12"><rect class="served" width="12" height="12"></rect></svg> полностью обслужено детей</p>
+<table>
+<tr><th>Бюджет</th><th>Позиций</th><th>Коэффициенты</th><th>Стоимость</th><th>Обслужено</th><th>Не подобрано</th><th>Средн. позиций</th></tr>
+{{- range .Bars}}
+<tr><td>{{.Outcome.MaxBudget}}</td><td>{{.Outcome.MaxCount}}</td><td>{{.Outcome.Coefficients}}</td><td>{{.Outcome.TotalCost}}</td><td>{{.Outcome.FullyServed}}/{{.Outcome.ChildrenCount}}</td><td>{{.Outcome.Failed}}</td><td>{{printf "%.2f" .Outcome.AverageItems}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+type scenarioBar struct {
+	Outcome     scenario.Outcome
+	Label       string
+	Y           int
+	ServedY     int
+	CostWidth   int
+	ServedWidth int
+	TextX       int
+}
+
+func writeScenarioHTML(out io.Writer, outcomes []scenario.Outcome) error {
+	const (
+		labelWidth = 360
+		barWidth   = 400
+		rowHeight  = 28
+	)
+
+	var maxCost domain.Money
+	for _, o := range outcomes {
+		maxCost = max(maxCost, o.TotalCost)
+	}
+
+	bars := make([]scenarioBar, 0, len(outcomes))
+	for i, o := range outcomes {
+		bar := scenarioBar{
+			Outcome: o,
+			Label:   o.Name,
+			Y:       i * rowHeight,
+			ServedY: i*rowHeight + 10,
+		}
+		if maxCost > 0 {
+			bar.CostWidth = int(o.TotalCost.Ratio(maxCost) * barWidth)
+		}
+		if o.ChildrenCount > 0 {
+			bar.ServedWidth = o.FullyServed * barWidth / o.ChildrenCount
+		}
+		bar.TextX = labelWidth + max(bar.CostWidth, bar.ServedWidth) + 6
+		bars = append(bars, bar)
+	}
+
+	return scenarioPage.Execute(out, map[string]any{
+		"Bars":       bars,
+		"LabelWidth": labelWidth,
+		"Width":      labelWidth + barWidth + 200,
+		"Height":     len(outcomes)*rowHeight + 4,
+	})
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"giftcalc/internal/application/logistics"
+	"giftcalc/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Расписание доставки с учетом часовых поясов",
+	Long: `Строит расписание рейсов плана загрузки (см. logistics): регионы
+обслуживаются в порядке наступления Нового года по местному времени,
+для каждого рейса рассчитывается окно выезда и время прибытия.
+Регионы, куда подарки не успевают до полуночи 31 декабря, отмечаются.`,
+	Run: runSchedule,
+}
+
+func init() {
+	scheduleCmd.
+		Flags().String("report", "report.json", "Файл отчета")
+	scheduleCmd.
+		Flags().String("catalog", "", "Файл каталога подарков, по умолчанию <data-dir>/catalog.json")
+	scheduleCmd.
+		Flags().String("regions", "", "Файл регионов с координатами, порядком маршрута и часовыми поясами, по умолчанию <data-dir>/regions.json")
+	scheduleCmd.
+		Flags().Float64("loadWeight", 0, "Вместимость рейса по весу, кг")
+	scheduleCmd.
+		Flags().Float64("loadVolume", 0, "Вместимость рейса по объему, л")
+	scheduleCmd.
+		Flags().String("start", "", "Выезд первого рейса (RFC 3339), по умолчанию 30 декабря 18:00 по Москве")
+	scheduleCmd.
+		Flags().Int("vehicles", 0, "Число саней, работающих одновременно")
+	scheduleCmd.
+		Flags().String("format", "text", "Формат вывода (text, json)")
+}
+
+func runSchedule(cmd *cobra.Command, args []string) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return
+	}
+
+	if format != "text" && format != "json" {
+		slog.Error("Неизвестный формат вывода", slog.String("format", format))
+		return
+	}
+
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	manifest, loadRules, report, err := planLoads(cmd, settings)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	rules := domain.DefaultScheduleRules
+	if settings.Schedule != nil {
+		rules = settings.Schedule.WithDefaults()
+	}
+	if v, _ := cmd.Flags().GetInt("vehicles"); v > 0 {
+		rules.Vehicles = v
+	}
+	if v, _ := cmd.Flags().GetString("start"); v != "" {
+		start, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			slog.Error("Некорректное время выезда", slog.String("start", v), slog.String("err", err.Error()))
+			return
+		}
+		rules.Start = start
+	}
+	if rules.Start.IsZero() {
+		moscow, err := time.LoadLocation(domain.DefaultTimeZone)
+		if err != nil {
+			slog.Error("Не найден часовой пояс", slog.String("err", err.Error()))
+			return
+		}
+		rules.Start = time.Date(report.GeneratedAt.Year(), time.December, 30, 18, 0, 0, 0, moscow)
+	}
+
+	warnUnroutedRegions(manifest, settings.Regions)
+
+	schedule, err := logistics.Schedule(manifest, settings.Regions, loadRules.Depot, rules, rules.Start.Year())
+	if err != nil {
+		slog.Error("Не смог построить расписание", slog.String("err", err.Error()))
+		return
+	}
+
+	if late := schedule.LateRegions(); len(late) > 0 {
+		slog.Warn("Подарки не успевают к Новому году", slog.Any("regions", late))
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(schedule, "", "  ")
+		if err != nil {
+			slog.Error("Не смог сформировать расписание", slog.String("err", err.Error()))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	renderSchedule(os.Stdout, schedule)
+}
+
+// warnUnroutedRegions предупреждает о регионах маршрута без часового пояса
+// или координат: для них берутся пояс и время в пути по умолчанию.
+func warnUnroutedRegions(manifest domain.LoadManifest, regions []domain.Region) {
+	index := make(map[string]domain.Region, len(regions))
+	for _, r := range regions {
+		index[r.Name] = r
+	}
+
+	var noZone, noLocation []string
+	for _, stop := range manifest.Route {
+		r := index[stop.Region]
+		if r.TimeZone == "" {
+			noZone = append(noZone, stop.Region)
+		}
+		if r.Location == nil {
+			noLocation = append(noLocation, stop.Region)
+		}
+	}
+
+	if len(noZone) > 0 {
+		slog.Warn("У регионов не задан часовой пояс, используется пояс по умолчанию", slog.Any("regions", noZone))
+	}
+	if len(noLocation) > 0 {
+		slog.Warn("У регионов не заданы координаты, используется время в пути по умолчанию", slog.Any("regions", noLocation))
+	}
+}
+
+// scheduleTime - формат времени расписания: местное время и смещение пояса.
+const scheduleTime = "02.01 15:04 MST"
+
+func renderSchedule(out io.Writer, schedule domain.DeliverySchedule) {
+	fmt.Fprintf(out, "Выезд первого рейса: %s\n", schedule.Start.Format(scheduleTime))
+
+	fmt.Fprintln(out, "\nРегионы в порядке наступления Нового года:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Регион\tПояс\tПолночь (UTC)\tПоследний рейс\tРейсов\t")
+	for _, r := range schedule.Regions {
+		status := ""
+		if r.Late {
+			status = "НЕ УСПЕВАЕТ"
+		}
+		arrival := "-"
+		if r.Loads > 0 {
+			arrival = r.LastArrival.Format(scheduleTime)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%d\t%s\n",
+			r.Region, r.TimeZone, r.Deadline.UTC().Format(scheduleTime), arrival, r.Loads, status)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nРейсы:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Рейс\tРегион\tСани\tВыезд\tВыезд не позже\tПрибытие\t")
+	for _, l := range schedule.Loads {
+		status := ""
+		if l.Late {
+			late := l.Arrival.Sub(l.Deadline).Round(time.Minute)
+			status = fmt.Sprintf("опоздание %d ч %02d мин", int(late.Hours()), int(late.Minutes())%60)
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			l.Number, l.Region, l.Vehicle,
+			l.DepartureFrom.UTC().Format(scheduleTime), l.DepartureBy.UTC().Format(scheduleTime),
+			l.Arrival.Format(scheduleTime), status)
+	}
+	w.Flush()
+}
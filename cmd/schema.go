@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <children|catalog|wishes|regions|report|history|scenario|notes|keywords|rules|certifications|rates>",
+	Short: "Сгенерировать JSON Schema для файлов данных",
+	Long: `Генерирует JSON Schema по типам домена.
+Схему можно подключить в редакторе для проверки файлов региональных отделений.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: kindNames(),
+	Run:       runSchema,
+}
+
+func init() {
+	schemaCmd.
+		Flags().String("out", "", "Файл для записи схемы (по умолчанию stdout)")
+}
+
+func runSchema(cmd *cobra.Command, args []string) {
+	kind, err := schema.ParseKind(args[0])
+	if err != nil {
+		slog.Error(err.Error())
+		return
+	}
+
+	out, err := cmd.Flags().GetString("out")
+	if err != nil {
+		return
+	}
+
+	s, err := schema.Generate(kind)
+	if err != nil {
+		slog.Error("Не смог сгенерировать схему", slog.String("err", err.Error()))
+		return
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		slog.Error("Не смог сформировать схему", slog.String("err", err.Error()))
+		return
+	}
+
+	if out == "" {
+		fmt.Println(string(data))
+		return
+	}
+
+	if err := os.WriteFile(out, data, 0644); err != nil {
+		slog.Error("Не смог записать файл '"+out+"'", slog.String("err", err.Error()))
+	}
+}
+
+func kindNames() []string {
+	kinds := schema.Kinds()
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = string(k)
+	}
+	return names
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"giftcalc/internal/application/calculation"
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/config"
+	"giftcalc/internal/infrastructure/httpapi"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Запустить локальный HTTP API",
+	Long: `Запускает HTTP сервер с JSON API:
+
+  POST /calculate       расчет по данным из тела запроса или файлам каталога данных
+  GET  /requirements    допустимые специальные требования
+  POST /validate?kind=  проверка документа по схеме (виды как в команде schema)
+  GET  /reports/{id}    отчет ранее выполненного расчета
+
+Отчеты хранятся в памяти. По SIGINT/SIGTERM сервер завершает начатые запросы.`,
+	Annotations: map[string]string{ownSignalsAnnotation: "true"},
+	Run:         runServe,
+}
+
+func init() {
+	serveCmd.
+		Flags().String("addr", ":8080", "Адрес HTTP сервера")
+	serveCmd.
+		Flags().Int64("maxBody", 10<<20, "Максимальный размер тела запроса, байт")
+	serveCmd.
+		Flags().Int("maxReports", 100, "Сколько последних отчетов хранить в памяти")
+	serveCmd.
+		Flags().Duration("shutdownTimeout", 10*time.Second, "Время на завершение начатых запросов при остановке")
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	addr, _ := cmd.Flags().GetString("addr")
+	maxBody, _ := cmd.Flags().GetInt64("maxBody")
+	maxReports, _ := cmd.Flags().GetInt("maxReports")
+	shutdownTimeout, _ := cmd.Flags().GetDuration("shutdownTimeout")
+
+	settings, configPath, err := resolveSettings(cmd)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+	if configPath != "" {
+		slog.Info("Используется файл конфигурации", slog.String("config", configPath))
+	}
+
+	server := httpapi.New(&serveBackend{settings: settings}, httpapi.Options{
+		MaxBodyBytes: maxBody,
+		MaxReports:   maxReports,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("HTTP API запущен", slog.String("addr", addr))
+	if err := httpapi.Serve(ctx, addr, server.Handler(), shutdownTimeout); err != nil {
+		slog.Error("Ошибка HTTP сервера", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+	slog.Info("HTTP API остановлен")
+}
+
+// serveBackend выполняет запросы API через те же функции, что и команды CLI.
+type serveBackend struct {
+	settings config.Settings
+}
+
+func (b *serveBackend) Calculate(req httpapi.CalculateRequest) (domain.Report, error) {
+	override := config.Settings{
+		MaxBudget: req.Options.MaxBudget,
+		MaxCount:  req.Options.MaxCount,
+		MaxWeight: req.Options.MaxWeight,
+		Strategy:  req.Options.Strategy,
+		Mode:      req.Options.Mode,
+	}
+	if err := override.Validate(); err != nil {
+		return domain.Report{}, badRequest(err)
+	}
+	settings := b.settings.Merge(override)
+
+	children := domain.ChildrenData{}
+	if err := requestData(schema.KindChildren, req.Children, req.ChildrenFile, settings.Children, &children); err != nil {
+		return domain.Report{}, err
+	}
+
+	catalog := domain.CatalogData{}
+	if err := requestData(schema.KindCatalog, req.Catalog, req.CatalogFile, settings.Catalog, &catalog); err != nil {
+		return domain.Report{}, err
+	}
+
+	var wishes []domain.Wish
+	if err := requestData(schema.KindWishes, req.Wishes, req.WishesFile, settings.Wishes, &wishes); err != nil {
+		return domain.Report{}, err
+	}
+
+	in, err := prepareInput(settings, children.Children, catalog.Items, wishes)
+	if err != nil {
+		return domain.Report{}, badRequest(err)
+	}
+
+	return calculation.Run(in, calculationOptions(settings, req.Options.Explain)), nil
+}
+
+func (b *serveBackend) Validate(kind schema.Kind, data []byte) error {
+	if err := checkSchema(kind, "запрос", data); err != nil {
+		return apiError(err)
+	}
+	if err := checkContent(kind, data); err != nil {
+		return &httpapi.Error{Status: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	return nil
+}
+
+// requestData декодирует данные из тела запроса, из файла внутри каталога
+// данных или, если не передано ни то, ни другое, из файла по умолчанию.
+// Пустой файл по умолчанию означает, что данные необязательны.
+func requestData(kind schema.Kind, inline json.RawMessage, file, fallback string, v any) error {
+	if len(inline) > 0 {
+		if err := checkSchema(kind, string(kind), inline); err != nil {
+			return apiError(err)
+		}
+		if err := json.Unmarshal(inline, v); err != nil {
+			return badRequest(err)
+		}
+		return nil
+	}
+
+	path := fallback
+	if file != "" {
+		if !filepath.IsLocal(file) {
+			return badRequest(fmt.Errorf("путь %q должен быть внутри каталога данных", file))
+		}
+		path = filepath.Join(dataDir, file)
+	}
+	if path == "" {
+		return nil
+	}
+
+	if err := readDataFile(kind, path, v); err != nil {
+		return apiError(err)
+	}
+	return nil
+}
+
+// apiError переводит ошибку чтения данных в ошибку API: нарушения схемы
+// возвращаются списком со статусом 422, остальное - как некорректный запрос.
+func apiError(err error) error {
+	var invalid *invalidFileError
+	if errors.As(err, &invalid) {
+		return &httpapi.Error{
+			Status:     http.StatusUnprocessableEntity,
+			Message:    invalid.Error(),
+			Violations: invalid.violations,
+		}
+	}
+	return badRequest(err)
+}
+
+func badRequest(err error) error {
+	return &httpapi.Error{Status: http.StatusBadRequest, Message: err.Error()}
+}
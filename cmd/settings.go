@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/config"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveSettings собирает параметры расчета из всех источников.
+// Приоритет: флаг > переменная окружения > профиль > defaults файла > встроенные значения.
+func resolveSettings(cmd *cobra.Command) (config.Settings, string, error) {
+	settings := config.Defaults(dataDir)
+
+	path, err := config.Discover(firstNonEmpty(configFile, os.Getenv(config.EnvPrefix+"CONFIG")), dataDir)
+	if err != nil {
+		return config.Settings{}, "", err
+	}
+
+	if path != "" {
+		file, err := config.Load(path)
+		if err != nil {
+			return config.Settings{}, "", err
+		}
+
+		fromFile, err := file.Resolve(firstNonEmpty(profile, os.Getenv(config.EnvPrefix+"PROFILE")))
+		if err != nil {
+			return config.Settings{}, "", err
+		}
+		settings = settings.Merge(fromFile)
+	}
+
+	fromEnv, err := config.FromEnv(os.LookupEnv)
+	if err != nil {
+		return config.Settings{}, "", err
+	}
+	settings = settings.Merge(fromEnv)
+
+	fromFlags, err := flagSettings(cmd)
+	if err != nil {
+		return config.Settings{}, "", err
+	}
+	if err := fromFlags.Validate(); err != nil {
+		return config.Settings{}, "", err
+	}
+	settings = settings.Merge(fromFlags)
+
+	if err := useKeywords(settings.Keywords); err != nil {
+		return config.Settings{}, "", err
+	}
+
+	if err := useRules(settings.Rules); err != nil {
+		return config.Settings{}, "", err
+	}
+
+	if err := useCertifications(settings.Certifications); err != nil {
+		return config.Settings{}, "", err
+	}
+
+	// Файл регионов по умолчанию необязателен, явно указанный должен существовать.
+	if settings.RegionsFile == config.Defaults(dataDir).RegionsFile {
+		if _, err := os.Stat(settings.RegionsFile); errors.Is(err, os.ErrNotExist) {
+			settings.RegionsFile = ""
+		}
+	}
+	if settings.RegionsFile != "" {
+		var regions []domain.Region
+		if err := readDataFile(schema.KindRegions, settings.RegionsFile, &regions); err != nil {
+			return config.Settings{}, "", err
+		}
+		settings.Regions = mergeRegions(regions, settings.Regions)
+	}
+
+	// Регионы из файла попадают в настройки только здесь, поэтому итоговые
+	// настройки проверяются еще раз: коэффициенты, тарифы и часовые пояса.
+	if err := settings.Validate(); err != nil {
+		return config.Settings{}, "", err
+	}
+
+	return settings, path, nil
+}
+
+// flagSettings возвращает только явно переданные флаги команды.
+func flagSettings(cmd *cobra.Command) (config.Settings, error) {
+	var s config.Settings
+	flags := cmd.Flags()
+
+	if flags.Changed("children") {
+		s.Children, _ = flags.GetString("children")
+	}
+	if flags.Changed("catalog") {
+		s.Catalog, _ = flags.GetString("catalog")
+	}
+	if flags.Changed("wishes") {
+		s.Wishes, _ = flags.GetString("wishes")
+	}
+	if flags.Changed("report") {
+		s.Report, _ = flags.GetString("report")
+	}
+	if flags.Changed("maxBudget") {
+		v, _ := flags.GetString("maxBudget")
+		budget, err := domain.ParseMoney(v)
+		if err != nil {
+			return config.Settings{}, fmt.Errorf("--maxBudget: %w", err)
+		}
+		s.MaxBudget = budget
+	}
+	if flags.Changed("maxCount") {
+		s.MaxCount, _ = flags.GetInt("maxCount")
+	}
+	if flags.Changed("maxWeight") {
+		s.MaxWeight, _ = flags.GetFloat64("maxWeight")
+	}
+	if flags.Changed("strategy") {
+		s.Strategy, _ = flags.GetString("strategy")
+	}
+	if flags.Changed("allocation") {
+		s.Allocation, _ = flags.GetString("allocation")
+	}
+	if flags.Changed("mode") {
+		s.Mode, _ = flags.GetString("mode")
+	}
+	if flags.Changed("history") {
+		s.HistoryFile, _ = flags.GetString("history")
+	}
+	if flags.Changed("dictionary") {
+		s.NotesDictionary, _ = flags.GetString("dictionary")
+	}
+	if flags.Changed("keywords") {
+		s.Keywords, _ = flags.GetString("keywords")
+	}
+	if flags.Changed("rules") {
+		s.Rules, _ = flags.GetString("rules")
+	}
+	if flags.Changed("certifications") {
+		s.Certifications, _ = flags.GetString("certifications")
+	}
+	if flags.Changed("rates") {
+		s.ExchangeRates, _ = flags.GetString("rates")
+	}
+	if flags.Changed("includeDelivery") {
+		v, _ := flags.GetBool("includeDelivery")
+		s.IncludeDelivery = &v
+	}
+	if flags.Changed("regions") {
+		s.RegionsFile, _ = flags.GetString("regions")
+	}
+
+	return s, nil
+}
+
+// useKeywords подключает словарь ключевых слов поверх встроенного, если файл существует.
+func useKeywords(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	var override domain.KeywordDictionary
+	if err := readDataFile(schema.KindKeywords, path, &override); err != nil {
+		return err
+	}
+
+	domain.UseKeywords(domain.BuiltinKeywords().Override(override))
+	slog.Debug("Подключен словарь ключевых слов",
+		slog.String("file", path),
+		slog.String("version", domain.KeywordsVersion()),
+	)
+	return nil
+}
+
+// mergeRegions дополняет коэффициенты из файла регионов настройками конфигурации.
+// Регионы из конфигурации перекрывают одноименные регионы файла; координаты
+// и порядок маршрута, не заданные в конфигурации, берутся из файла.
+func mergeRegions(base, override []domain.Region) []domain.Region {
+	index := make(map[string]int, len(base))
+	result := append([]domain.Region(nil), base...)
+	for i, r := range result {
+		index[r.Name] = i
+	}
+
+	for _, r := range override {
+		if i, ok := index[r.Name]; ok {
+			if r.Location == nil {
+				r.Location = result[i].Location
+			}
+			if r.Order == 0 {
+				r.Order = result[i].Order
+			}
+			if r.TimeZone == "" {
+				r.TimeZone = result[i].TimeZone
+			}
+			if r.Delivery == nil {
+				r.Delivery = result[i].Delivery
+			}
+			result[i] = r
+			continue
+		}
+		index[r.Name] = len(result)
+		result = append(result, r)
+	}
+
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// useRules подключает правила соответствия поверх встроенных, если файл существует.
+func useRules(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	var override domain.RulesDictionary
+	if err := readDataFile(schema.KindRules, path, &override); err != nil {
+		return err
+	}
+
+	if err := domain.UseRules(domain.BuiltinRules().Override(override)); err != nil {
+		return fmt.Errorf("файл '%s': %w", path, err)
+	}
+	slog.Debug("Подключены правила соответствия",
+		slog.String("file", path),
+		slog.String("version", domain.RulesVersion()),
+	)
+	return nil
+}
+
+// useCertifications подключает реестр сертификатов, если файл существует.
+func useCertifications(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	registry, err := loadCertifications(path)
+	if err != nil {
+		return err
+	}
+
+	domain.UseCertifications(registry)
+	slog.Debug("Подключен реестр сертификатов",
+		slog.String("file", path),
+		slog.String("version", registry.Version),
+	)
+	return nil
+}
+
+// loadCertifications читает и проверяет реестр сертификатов.
+func loadCertifications(path string) (domain.CertificationRegistry, error) {
+	var registry domain.CertificationRegistry
+	if err := readDataFile(schema.KindCertifications, path, &registry); err != nil {
+		return registry, err
+	}
+	if err := registry.Validate(); err != nil {
+		return registry, fmt.Errorf("файл '%s': %w", path, err)
+	}
+	return registry, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Проверить файлы данных по JSON Schema",
+	Run:   runValidate,
+}
+
+func init() {
+	validateCmd.
+		Flags().String("children", "", "Файл с данными о детях (JSON)")
+	validateCmd.
+		Flags().String("catalog", "", "Файл каталога подарков")
+	validateCmd.
+		Flags().String("wishes", "", "Файл с пожеланиями детей")
+	validateCmd.
+		Flags().String("regions", "", "Файл региональных коэффициентов")
+	validateCmd.
+		Flags().String("report", "", "Файл отчета")
+	validateCmd.
+		Flags().String("history", "", "Файл истории подарков")
+	validateCmd.
+		Flags().String("scenario", "", "Файл сетки сценариев")
+	validateCmd.
+		Flags().String("notes", "", "Словарь разбора заметок")
+	validateCmd.
+		Flags().String("keywords", "", "Словарь ключевых слов")
+	validateCmd.
+		Flags().String("rules", "", "Правила соответствия требованиям")
+	validateCmd.
+		Flags().String("certifications", "", "Реестр сертификатов; вместе с --catalog проверяются сертификаты предметов")
+	validateCmd.
+		Flags().String("rates", "", "Таблица курсов валют")
+}
+
+func runValidate(cmd *cobra.Command, args []string) {
+	files := []struct {
+		flag string
+		kind schema.Kind
+	}{
+		{"children", schema.KindChildren},
+		{"catalog", schema.KindCatalog},
+		{"wishes", schema.KindWishes},
+		{"regions", schema.KindRegions},
+		{"report", schema.KindReport},
+		{"history", schema.KindHistory},
+		{"scenario", schema.KindScenario},
+		{"notes", schema.KindNotes},
+		{"keywords", schema.KindKeywords},
+		{"rules", schema.KindRules},
+		{"certifications", schema.KindCertifications},
+		{"rates", schema.KindRates},
+	}
+
+	checked := 0
+	failed := 0
+	for _, f := range files {
+		path, err := cmd.Flags().GetString(f.flag)
+		if err != nil || path == "" {
+			continue
+		}
+		checked++
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Error("Не могу найти файл '" + path + "'")
+			failed++
+			continue
+		}
+
+		if err := checkSchema(f.kind, path, data); err != nil {
+			logFileError(err)
+			failed++
+			continue
+		}
+
+		if err := checkContent(f.kind, data); err != nil {
+			slog.Error("Ошибка в содержимом файла", slog.String("file", path), slog.String("err", err.Error()))
+			failed++
+			continue
+		}
+
+		slog.Info("Файл соответствует схеме", slog.String("file", path), slog.String("kind", string(f.kind)))
+	}
+
+	if checked == 0 {
+		slog.Error("Необходимо передать хотя бы один файл для проверки")
+		return
+	}
+
+	if failed == 0 {
+		checkCatalogCertifications(cmd)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Проверка не пройдена: файлов с ошибками %d из %d\n", failed, checked)
+		os.Exit(1)
+	}
+}
+
+// checkContent проверяет содержимое, которое не выражается схемой:
+// выражения правил соответствия, согласованность реестра сертификатов и курсы валют.
+func checkContent(kind schema.Kind, data []byte) error {
+	switch kind {
+	case schema.KindRules:
+		var override domain.RulesDictionary
+		if err := json.Unmarshal(data, &override); err != nil {
+			return err
+		}
+		return domain.BuiltinRules().Override(override).Validate()
+	case schema.KindCertifications:
+		var registry domain.CertificationRegistry
+		if err := json.Unmarshal(data, &registry); err != nil {
+			return err
+		}
+		return registry.Validate()
+	case schema.KindRates:
+		var rates domain.ExchangeRates
+		if err := json.Unmarshal(data, &rates); err != nil {
+			return err
+		}
+		return rates.Validate()
+	}
+	return nil
+}
+
+// checkCatalogCertifications сверяет сертификаты каталога с реестром,
+// если переданы оба файла. Неизвестные и просроченные сертификаты выводятся
+// предупреждениями: при подборе они не подтверждают свойства предмета.
+func checkCatalogCertifications(cmd *cobra.Command) {
+	catalogPath, _ := cmd.Flags().GetString("catalog")
+	registryPath, _ := cmd.Flags().GetString("certifications")
+	if catalogPath == "" || registryPath == "" {
+		return
+	}
+
+	var catalog domain.CatalogData
+	if err := readDataFile(schema.KindCatalog, catalogPath, &catalog); err != nil {
+		logFileError(err)
+		return
+	}
+	registry, err := loadCertifications(registryPath)
+	if err != nil {
+		logFileError(err)
+		return
+	}
+
+	issues := registry.CheckCertifications(catalog.Items, time.Now())
+	for _, issue := range issues {
+		if issue.Unknown() {
+			slog.Warn("Сертификат отсутствует в реестре",
+				slog.Int("item_id", issue.ItemID),
+				slog.String("item", issue.ItemName),
+				slog.String("certification", issue.Certification),
+			)
+			continue
+		}
+		slog.Warn("Срок действия сертификата истек",
+			slog.Int("item_id", issue.ItemID),
+			slog.String("item", issue.ItemName),
+			slog.String("certification", issue.Certification),
+			slog.String("expires", issue.Expires),
+		)
+	}
+
+	slog.Info("Сертификаты каталога проверены",
+		slog.String("registry", registry.Version),
+		slog.Int("issues", len(issues)),
+	)
+}
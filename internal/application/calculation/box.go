@@ -0,0 +1,38 @@
+package calculation
+
+import (
+	"fmt"
+
+	"giftcalc/internal/application/packing"
+	"giftcalc/internal/domain"
+)
+
+// assignBox подбирает подарку наименьшую подходящую стандартную коробку.
+// Предметы без габаритов в укладке не участвуют, о них пишется примечание.
+func assignBox(result *domain.ChildResult, dimensions map[int]domain.Dimensions, boxes []domain.BoxSize) {
+	items := make([]domain.Dimensions, 0, len(result.GiftSelection))
+	var unsized []int
+	for _, item := range result.GiftSelection {
+		d, ok := dimensions[item.ItemID]
+		if !ok {
+			unsized = append(unsized, item.ItemID)
+			continue
+		}
+		items = append(items, d)
+	}
+
+	if len(items) == 0 {
+		return
+	}
+	if len(unsized) > 0 {
+		result.SelectionNotes = append(result.SelectionNotes,
+			fmt.Sprintf("Габариты предметов %v не указаны, коробка подобрана без них", unsized))
+	}
+
+	box, ok := packing.SmallestBox(items, result.CostSummary.Weight, boxes)
+	if !ok {
+		result.Warnings = append(result.Warnings, "Подарок не помещается ни в одну стандартную коробку")
+		return
+	}
+	result.Box = box.Name
+}
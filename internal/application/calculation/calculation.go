@@ -0,0 +1,370 @@
+package calculation
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"giftcalc/internal/application/selection"
+	"giftcalc/internal/domain"
+)
+
+// ReportVersion - версия формата отчета.
+const ReportVersion = "v1.0.0"
+
+// Input содержит входные данные расчета.
+type Input struct {
+	Children []domain.Child
+	Catalog  []domain.CatalogItem
+	Wishes   []domain.Wish
+	History  *domain.GiftHistory
+
+	// Currency - базовая валюта и курсы, по которым цены каталога
+	// уже пересчитаны в базовую валюту (см. ExchangeRates.ConvertCatalog).
+	Currency domain.ReportCurrency
+}
+
+// Options содержит параметры расчета.
+type Options struct {
+	MaxCount     int
+	MaxBudget    domain.Money
+	MaxWeight    float64
+	Strategy     string
+	Allocation   string
+	Coefficients map[string]float64
+	Composition  *domain.CompositionRules
+	Mode         string
+	Templates    []domain.GiftTemplate
+	Household    *domain.HouseholdRules
+	History      *domain.HistoryRules
+	Severity     domain.RequirementSeverity
+	SafetyPolicy *domain.SafetyPolicy
+	Explain      bool
+
+	// Delivery - модель стоимости доставки, nil - доставка не учитывается.
+	// DeliveryTariffs - тарифы доставки по названию региона.
+	// IncludeDelivery - доставка входит в бюджет подарка.
+	Delivery        *domain.DeliveryRules
+	DeliveryTariffs map[string]domain.DeliveryTariff
+	IncludeDelivery bool
+
+	// Boxes - стандартные размеры коробок; пусто - коробки не подбираются.
+	Boxes []domain.BoxSize
+}
+
+// Run выполняет подбор подарков для всех детей и формирует отчет.
+// Результаты в отчете идут в порядке входного файла независимо от порядка обработки.
+// Требования детей дополняются возрастной политикой безопасности до подбора;
+// в отчете добавленные требования указываются отдельно от исходных.
+func Run(in Input, opts Options) domain.Report {
+	original := in.Children
+	children, policyReqs, policyNotes := applySafetyPolicy(in.Children, opts.SafetyPolicy)
+	in.Children = children
+
+	items := OrderCatalog(in.Catalog, opts.Strategy)
+	wishes := selection.IndexWishes(in.Wishes)
+
+	var stock *selection.Stock
+	order := make([]int, len(in.Children))
+	for i := range order {
+		order[i] = i
+	}
+	if opts.Allocation == domain.AllocationStock {
+		stock = selection.NewStock(in.Catalog)
+		order = selection.AllocationOrder(in.Children, wishes)
+	}
+
+	year := time.Now().Year()
+	families := households(in.Children)
+	familyItems := selection.WithoutShared(items, opts.Household)
+
+	picks := make([]pick, len(in.Children))
+	for _, idx := range order {
+		child := in.Children[idx]
+		childLog := childLogger(child)
+		childLog.Debug("Подбор подарка", slog.String("requirements", child.SpecialRequirements.String()))
+
+		if opts.History != nil {
+			picks[idx].past = in.History.Past(child.ID, year, opts.History.Years)
+		}
+		params := childParams(child, opts, stock)
+		params.Past = picks[idx].past
+
+		if opts.Mode == domain.ModeTemplate {
+			picks[idx].template = domain.FindTemplate(opts.Templates, &child)
+			if picks[idx].template == nil {
+				msg := fmt.Sprintf("Нет шаблона подарка для возрастной группы %s", child.AgeGroup())
+				childLog.Warn(msg)
+				picks[idx].err = &msg
+			} else {
+				picks[idx].selected, picks[idx].notes = selection.FillTemplate(child, picks[idx].template, items, params)
+			}
+		} else if siblings, ok := families[child.FamilyID]; ok {
+			given := givenToSiblings(picks, siblings, idx)
+			ranked := selection.PreferMatching(familyItems, child, opts.Severity)
+			candidates := selection.PreferUnused(wishes.PreferWished(child.ID, ranked), given)
+			candidates = selection.PreferFresh(candidates, opts.History, params.Past)
+			picks[idx].selected = selection.Select(child, candidates, params)
+		} else {
+			ranked := selection.PreferMatching(items, child, opts.Severity)
+			candidates := selection.PreferFresh(wishes.PreferWished(child.ID, ranked), opts.History, params.Past)
+			picks[idx].selected = selection.Select(child, candidates, params)
+		}
+
+		childLog.Debug("Подарок подобран",
+			slog.Int("items_count", len(picks[idx].selected.Items)),
+			slog.Any("cost", picks[idx].selected.Cost),
+		)
+	}
+
+	var summaries []domain.HouseholdSummary
+	if opts.Mode == domain.ModeTemplate && len(families) > 0 {
+		slog.Warn("Правила для семей не применяются в режиме template", slog.Int("families", len(families)))
+		for _, siblings := range families {
+			for _, idx := range siblings {
+				picks[idx].householdNotes = append(picks[idx].householdNotes,
+					"Подарок собран по шаблону, правила для семей не применялись")
+			}
+		}
+	}
+	if opts.Mode != domain.ModeTemplate {
+		for _, familyID := range sortedKeys(families) {
+			siblings := families[familyID]
+			balanceHousehold(in.Children, picks, siblings, familyItems, wishes, opts, stock)
+			summaries = append(summaries, householdSummary(familyID, in.Children, picks, siblings, items, opts, stock))
+		}
+	}
+
+	dimensions := make(map[int]domain.Dimensions)
+	for _, item := range in.Catalog {
+		if item.Dimensions != nil {
+			dimensions[item.Id] = *item.Dimensions
+		}
+	}
+
+	results := make([]domain.ChildResult, len(in.Children))
+	var substitutions []domain.StockSubstitution
+
+	for idx, child := range in.Children {
+		p := picks[idx]
+		result := domain.ChildResult{
+			ChildID:             child.ID,
+			ChildName:           child.Name,
+			Age:                 child.Age,
+			Region:              child.Region,
+			SpecialRequirements: original[idx].SpecialRequirements,
+			PolicyRequirements:  policyReqs[idx],
+			GiftSelection:       p.selected.Items,
+			SelectionTrace:      p.selected.Trace,
+			CostSummary: domain.ChildCostSummary{
+				Cost:       p.selected.Cost,
+				Weight:     p.selected.Weight,
+				ItemsCount: len(p.selected.Items),
+			},
+			SelectionNotes: append(policyNotes[idx], p.householdNotes...),
+			Warnings:       p.notes,
+			Errors:         p.err,
+		}
+
+		if opts.Delivery != nil && len(p.selected.Items) > 0 {
+			tariff := opts.Delivery.Tariff(child.Region, opts.DeliveryTariffs)
+			result.CostSummary.Delivery = tariff.Cost(p.selected.Weight)
+			result.CostSummary.Total = result.CostSummary.Cost + result.CostSummary.Delivery
+		}
+
+		if len(opts.Boxes) > 0 && len(p.selected.Items) > 0 {
+			assignBox(&result, dimensions, opts.Boxes)
+		}
+
+		if p.template != nil {
+			result.Template = p.template.Name
+		} else if p.err == nil {
+			result.Warnings = append(result.Warnings, opts.Composition.Check(&child, p.selected.Items)...)
+		}
+
+		missing, substitutes, unreplaced := p.selected.Substitutions()
+		if len(p.selected.Shortages) > 0 {
+			childLogger(child).Debug("Нехватка запаса", slog.Any("shortages", p.selected.Shortages))
+		}
+		if len(missing) > 0 {
+			result.SelectionNotes = append(result.SelectionNotes,
+				fmt.Sprintf("Предметы %v закончились на складе, подобрана замена %v", missing, substitutes))
+			substitutions = append(substitutions, domain.StockSubstitution{
+				ChildID:           child.ID,
+				ChildName:         child.Name,
+				Region:            child.Region,
+				MissingItemIDs:    missing,
+				SubstituteItemIDs: substitutes,
+			})
+		}
+		if len(unreplaced) > 0 {
+			result.SelectionNotes = append(result.SelectionNotes,
+				fmt.Sprintf("Предметы %v закончились на складе, замена не найдена", unreplaced))
+		}
+
+		results[idx] = result
+	}
+
+	report := domain.Report{
+		Version:        ReportVersion,
+		GeneratedAt:    time.Now(),
+		Results:        results,
+		Households:     summaries,
+		Currency:       &in.Currency,
+		RegionAnalysis: AnalyzeRegions(results, opts.Coefficients),
+	}
+	if in.Currency.Base == "" {
+		report.Currency = &domain.ReportCurrency{Base: domain.DefaultCurrency}
+	}
+
+	//TODO: статистику
+	report.AgeGroupAnalysis = domain.AgeGroupAnalysis{
+		ChildrenCount: len(in.Children),
+	}
+
+	if stock != nil {
+		sort.Slice(substitutions, func(i, j int) bool { return substitutions[i].ChildID < substitutions[j].ChildID })
+		report.StockAllocation = &domain.StockAllocation{
+			Items:         stock.Consumption(),
+			Substitutions: substitutions,
+		}
+	}
+
+	if opts.Mode == domain.ModeTemplate {
+		report.TemplateUsage = templateUsage(results)
+	}
+
+	if opts.History != nil {
+		report.HistorySummary = historySummary(picks, opts.History.Years)
+	}
+
+	return report
+}
+
+// pick - промежуточный результат подбора для одного ребенка.
+type pick struct {
+	selected       selection.Result
+	template       *domain.GiftTemplate
+	notes          []string
+	householdNotes []string
+	past           domain.PastGifts
+	err            *string
+}
+
+// applySafetyPolicy применяет политику безопасности к каждому ребенку
+// и возвращает добавленные требования и примечания по индексу ребенка.
+func applySafetyPolicy(children []domain.Child, policy *domain.SafetyPolicy) ([]domain.Child, [][]domain.SafetyRequirement, [][]string) {
+	result := make([]domain.Child, len(children))
+	added := make([][]domain.SafetyRequirement, len(children))
+	notes := make([][]string, len(children))
+	for i, child := range children {
+		result[i], added[i], notes[i] = policy.Apply(child)
+	}
+	return result, added, notes
+}
+
+func childLogger(child domain.Child) *slog.Logger {
+	return slog.With(
+		slog.Int("child_id", child.ID),
+		slog.String("region", child.Region),
+		slog.Int("age", child.Age),
+	)
+}
+
+func childParams(child domain.Child, opts Options, stock *selection.Stock) selection.Params {
+	return selection.Params{
+		MaxCount:    opts.MaxCount,
+		MaxBudget:   opts.MaxBudget,
+		MaxWeight:   opts.MaxWeight,
+		Coefficient: opts.Coefficients[child.Region],
+		Explain:     opts.Explain,
+		Stock:       stock,
+		Composition: opts.Composition,
+		History:     opts.History,
+		Severity:    opts.Severity,
+		Delivery:    budgetDelivery(child, opts),
+	}
+}
+
+// budgetDelivery возвращает тариф доставки ребенка, если доставка входит в бюджет.
+func budgetDelivery(child domain.Child, opts Options) *domain.DeliveryTariff {
+	if opts.Delivery == nil || !opts.IncludeDelivery {
+		return nil
+	}
+	tariff := opts.Delivery.Tariff(child.Region, opts.DeliveryTariffs)
+	return &tariff
+}
+
+// historySummary считает, сколько детей получили что-то новое относительно прошлых лет.
+func historySummary(picks []pick, years int) *domain.HistorySummary {
+	summary := &domain.HistorySummary{Years: years}
+	for _, p := range picks {
+		if !p.past.Empty() {
+			summary.ChildrenWithHistory++
+		}
+
+		fresh := false
+		for _, item := range p.selected.Items {
+			if p.past.Items[item.ItemID] {
+				summary.RepeatedItems++
+			} else {
+				fresh = true
+			}
+		}
+		if fresh {
+			summary.ChildrenWithNewItems++
+		}
+	}
+	return summary
+}
+
+// templateUsage считает, сколько детей получили каждый шаблон и сколько
+// различных наборов предметов придется собрать на производстве.
+func templateUsage(results []domain.ChildResult) []domain.TemplateUsage {
+	children := make(map[string]int)
+	variants := make(map[string]map[string]bool)
+	for _, r := range results {
+		if r.Template == "" {
+			continue
+		}
+
+		ids := make([]string, 0, len(r.GiftSelection))
+		for _, item := range r.GiftSelection {
+			ids = append(ids, fmt.Sprint(item.ItemID))
+		}
+		slices.Sort(ids)
+
+		if variants[r.Template] == nil {
+			variants[r.Template] = make(map[string]bool)
+		}
+		variants[r.Template][strings.Join(ids, ",")] = true
+		children[r.Template]++
+	}
+
+	usage := make([]domain.TemplateUsage, 0, len(children))
+	for name, n := range children {
+		usage = append(usage, domain.TemplateUsage{
+			Template: name,
+			Children: n,
+			Variants: len(variants[name]),
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Template < usage[j].Template })
+	return usage
+}
+
+// OrderCatalog возвращает каталог в порядке перебора для выбранной стратегии.
+func OrderCatalog(items []domain.CatalogItem, strategy string) []domain.CatalogItem {
+	if strategy != domain.StrategyCheapestFirst {
+		return items
+	}
+
+	ordered := append([]domain.CatalogItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Price < ordered[j].Price
+	})
+	return ordered
+}
@@ -0,0 +1,197 @@
+package calculation
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"giftcalc/internal/application/selection"
+	"giftcalc/internal/domain"
+)
+
+// households группирует детей по семьям. Семьи из одного ребенка не учитываются.
+func households(children []domain.Child) map[string][]int {
+	all := make(map[string][]int)
+	for i, child := range children {
+		if child.FamilyID != "" {
+			all[child.FamilyID] = append(all[child.FamilyID], i)
+		}
+	}
+
+	result := make(map[string][]int)
+	for id, members := range all {
+		if len(members) > 1 {
+			result[id] = members
+		}
+	}
+	return result
+}
+
+// givenToSiblings возвращает предметы, уже подобранные остальным детям семьи.
+func givenToSiblings(picks []pick, siblings []int, self int) map[int]bool {
+	given := make(map[int]bool)
+	for _, i := range siblings {
+		if i == self {
+			continue
+		}
+		for _, item := range picks[i].selected.Items {
+			given[item.ItemID] = true
+		}
+	}
+	return given
+}
+
+// balanceHousehold пересобирает слишком дорогие подарки семьи так, чтобы их
+// стоимость не превышала самый дешевый непустой подарок больше чем на допустимое отклонение.
+func balanceHousehold(
+	children []domain.Child,
+	picks []pick,
+	siblings []int,
+	items []domain.CatalogItem,
+	wishes selection.WishIndex,
+	opts Options,
+	stock *selection.Stock,
+) {
+	tolerance := opts.Household.Tolerance()
+	if tolerance <= 0 {
+		return
+	}
+
+	lowest, ok := lowestCost(picks, siblings)
+	if !ok {
+		return
+	}
+	limit := lowest.MulCoefficient(1 + tolerance)
+
+	for _, idx := range siblings {
+		if picks[idx].selected.Cost <= limit {
+			continue
+		}
+
+		child := children[idx]
+		for _, item := range picks[idx].selected.Items {
+			stock.Release(item.ItemID)
+		}
+
+		params := childParams(child, opts, stock)
+		// limit сравнивается со стоимостью предметов, а MaxBudget может включать доставку.
+		params.MaxCost = limit
+		params.Past = picks[idx].past
+
+		given := givenToSiblings(picks, siblings, idx)
+		ranked := selection.PreferMatching(items, child, opts.Severity)
+		candidates := selection.PreferUnused(wishes.PreferWished(child.ID, ranked), given)
+		candidates = selection.PreferFresh(candidates, opts.History, params.Past)
+
+		childLogger(child).Debug("Выравнивание подарка в семье",
+			slog.String("family_id", child.FamilyID),
+			slog.Any("cost", picks[idx].selected.Cost),
+			slog.Any("limit", limit),
+		)
+
+		picks[idx].selected = selection.Select(child, candidates, params)
+		picks[idx].householdNotes = append(picks[idx].householdNotes,
+			fmt.Sprintf("Бюджет ограничен %s для выравнивания с подарками братьев и сестер", limit))
+	}
+}
+
+// householdSummary подбирает общие предметы семьи и формирует сводку.
+func householdSummary(
+	familyID string,
+	children []domain.Child,
+	picks []pick,
+	siblings []int,
+	items []domain.CatalogItem,
+	opts Options,
+	stock *selection.Stock,
+) domain.HouseholdSummary {
+	summary := domain.HouseholdSummary{FamilyID: familyID}
+
+	members := make([]domain.Child, 0, len(siblings))
+	for _, idx := range siblings {
+		members = append(members, children[idx])
+		summary.ChildIDs = append(summary.ChildIDs, children[idx].ID)
+
+		cost := picks[idx].selected.Cost
+		summary.TotalCost += cost
+		if len(summary.ChildIDs) == 1 || cost < summary.MinCost {
+			summary.MinCost = cost
+		}
+		summary.MaxCost = max(summary.MaxCost, cost)
+	}
+
+	lowest, ok := lowestCost(picks, siblings)
+	summary.Balanced = !ok || summary.MaxCost <= lowest.MulCoefficient(1+opts.Household.Tolerance())
+
+	if opts.Household == nil {
+		return summary
+	}
+
+	carrier := householdCarrier(children, siblings)
+	summary.Region = children[carrier].Region
+	params := childParams(children[carrier], opts, stock)
+	for _, category := range opts.Household.SharedCategories {
+		shared, ok := selection.SelectShared(members, items, category, picks[carrier].selected, params)
+		if !ok {
+			for _, idx := range siblings {
+				picks[idx].householdNotes = append(picks[idx].householdNotes,
+					fmt.Sprintf("Общий предмет категории %s для семьи не подобран", category))
+			}
+			continue
+		}
+
+		summary.SharedItems = append(summary.SharedItems, shared)
+		summary.CarrierID = children[carrier].ID
+		summary.TotalCost += shared.Price
+		picks[carrier].selected.AddShared(shared)
+		for _, idx := range siblings {
+			picks[idx].householdNotes = append(picks[idx].householdNotes,
+				fmt.Sprintf("Общий предмет семьи: %s", shared.ItemName))
+		}
+	}
+
+	return summary
+}
+
+// householdCarrier выбирает ребенка, в подарок которого кладутся общие
+// предметы: первого ребенка из региона, где живет больше всего детей семьи.
+// Регион этого ребенка считается регионом семьи.
+func householdCarrier(children []domain.Child, siblings []int) int {
+	counts := make(map[string]int, len(siblings))
+	for _, idx := range siblings {
+		counts[children[idx].Region]++
+	}
+
+	carrier := siblings[0]
+	for _, idx := range siblings {
+		if counts[children[idx].Region] > counts[children[carrier].Region] {
+			carrier = idx
+		}
+	}
+	return carrier
+}
+
+// lowestCost возвращает стоимость самого дешевого непустого подарка семьи.
+func lowestCost(picks []pick, siblings []int) (domain.Money, bool) {
+	var lowest domain.Money
+	found := false
+	for _, idx := range siblings {
+		selected := picks[idx].selected
+		if len(selected.Items) == 0 {
+			continue
+		}
+		if !found || selected.Cost < lowest {
+			lowest, found = selected.Cost, true
+		}
+	}
+	return lowest, found
+}
+
+func sortedKeys(m map[string][]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,41 @@
+package calculation
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// AnalyzeRegions сводит стоимость подарков и доставки по регионам.
+// Регионы упорядочены по названию; средняя стоимость считается по подаркам
+// без доставки.
+func AnalyzeRegions(results []domain.ChildResult, coefficients map[string]float64) []domain.RegionAnalysis {
+	index := make(map[string]int)
+	var analysis []domain.RegionAnalysis
+
+	for i := range results {
+		r := &results[i]
+		idx, ok := index[r.Region]
+		if !ok {
+			coefficient := coefficients[r.Region]
+			if coefficient <= 0 {
+				coefficient = 1.0
+			}
+			idx = len(analysis)
+			index[r.Region] = idx
+			analysis = append(analysis, domain.RegionAnalysis{Region: r.Region, Coefficient: coefficient})
+		}
+
+		a := &analysis[idx]
+		a.ChildrenCount++
+		a.TotalCost += r.CostSummary.Cost
+		a.TotalWeight += r.CostSummary.Weight
+		a.DeliveryCost += r.CostSummary.Delivery
+	}
+
+	for i := range analysis {
+		analysis[i].AverageCost = analysis[i].TotalCost.Div(analysis[i].ChildrenCount)
+	}
+	sort.Slice(analysis, func(i, j int) bool { return analysis[i].Region < analysis[j].Region })
+	return analysis
+}
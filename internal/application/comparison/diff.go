@@ -0,0 +1,242 @@
+package comparison
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// ItemRef - предмет в сравнении отчетов.
+type ItemRef struct {
+	ItemID   int    `json:"item_id"`
+	ItemName string `json:"item_name"`
+}
+
+// ChildDiff - изменения подарка одного ребенка.
+type ChildDiff struct {
+	ChildID      int          `json:"child_id"`
+	ChildName    string       `json:"child_name"`
+	Region       string       `json:"region"`
+	AgeGroup     string       `json:"age_group"`
+	AddedItems   []ItemRef    `json:"added_items,omitempty"`
+	RemovedItems []ItemRef    `json:"removed_items,omitempty"`
+	OldCost      domain.Money `json:"old_cost"`
+	NewCost      domain.Money `json:"new_cost"`
+	CostDelta    domain.Money `json:"cost_delta"`
+	WasFailing   bool         `json:"was_failing"`
+	IsFailing    bool         `json:"is_failing"`
+}
+
+// GroupDelta - суммарное изменение стоимости по региону или возрастной группе.
+type GroupDelta struct {
+	Name      string       `json:"name"`
+	Children  int          `json:"children"`
+	OldCost   domain.Money `json:"old_cost"`
+	NewCost   domain.Money `json:"new_cost"`
+	CostDelta domain.Money `json:"cost_delta"`
+}
+
+// ReportDiff - результат сравнения двух отчетов.
+type ReportDiff struct {
+	// Children - дети, у которых изменился подарок или его стоимость.
+	Children []ChildDiff `json:"children"`
+
+	// AddedChildren и RemovedChildren - дети, которые есть только в одном из отчетов.
+	AddedChildren   []int `json:"added_children,omitempty"`
+	RemovedChildren []int `json:"removed_children,omitempty"`
+
+	// StartedFailing и StoppedFailing - дети, для которых подарок перестал или начал подбираться.
+	StartedFailing []int `json:"started_failing,omitempty"`
+	StoppedFailing []int `json:"stopped_failing,omitempty"`
+
+	Regions   []GroupDelta `json:"regions"`
+	AgeGroups []GroupDelta `json:"age_groups"`
+
+	OldTotal  domain.Money `json:"old_total"`
+	NewTotal  domain.Money `json:"new_total"`
+	CostDelta domain.Money `json:"cost_delta"`
+}
+
+// Failing сообщает, что подарок ребенку не подобран.
+func Failing(r *domain.ChildResult) bool {
+	return r != nil && (r.Errors != nil || len(r.GiftSelection) == 0)
+}
+
+// Compare сравнивает два отчета по ChildID.
+func Compare(oldReport, newReport domain.Report) ReportDiff {
+	oldByID := indexResults(oldReport.Results)
+	newByID := indexResults(newReport.Results)
+
+	ids := make([]int, 0, len(oldByID)+len(newByID))
+	for id := range oldByID {
+		ids = append(ids, id)
+	}
+	for id := range newByID {
+		if _, ok := oldByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	diff := ReportDiff{}
+	regions := newGroups()
+	ageGroups := newGroups()
+
+	for _, id := range ids {
+		oldResult, newResult := oldByID[id], newByID[id]
+
+		switch {
+		case oldResult == nil:
+			diff.AddedChildren = append(diff.AddedChildren, id)
+		case newResult == nil:
+			diff.RemovedChildren = append(diff.RemovedChildren, id)
+		}
+
+		c := compareChild(oldResult, newResult)
+
+		if oldResult != nil && newResult != nil {
+			if !c.WasFailing && c.IsFailing {
+				diff.StartedFailing = append(diff.StartedFailing, id)
+			}
+			if c.WasFailing && !c.IsFailing {
+				diff.StoppedFailing = append(diff.StoppedFailing, id)
+			}
+		}
+
+		if len(c.AddedItems) > 0 || len(c.RemovedItems) > 0 || c.CostDelta != 0 || c.WasFailing != c.IsFailing {
+			diff.Children = append(diff.Children, c)
+		}
+
+		regions.add(c.Region, c)
+		ageGroups.add(c.AgeGroup, c)
+
+		diff.OldTotal += c.OldCost
+		diff.NewTotal += c.NewCost
+	}
+
+	diff.CostDelta = diff.NewTotal - diff.OldTotal
+	diff.Regions = regions.list(nil)
+	diff.AgeGroups = ageGroups.list(domain.AllAgeGroups())
+
+	return diff
+}
+
+func indexResults(results []domain.ChildResult) map[int]*domain.ChildResult {
+	index := make(map[int]*domain.ChildResult, len(results))
+	for i := range results {
+		index[results[i].ChildID] = &results[i]
+	}
+	return index
+}
+
+// compareChild сравнивает подарки ребенка. Любой из результатов может отсутствовать.
+func compareChild(oldResult, newResult *domain.ChildResult) ChildDiff {
+	base := newResult
+	if base == nil {
+		base = oldResult
+	}
+
+	child := domain.Child{Age: base.Age}
+	c := ChildDiff{
+		ChildID:    base.ChildID,
+		ChildName:  base.ChildName,
+		Region:     base.Region,
+		AgeGroup:   child.AgeGroup(),
+		WasFailing: Failing(oldResult),
+		IsFailing:  Failing(newResult),
+	}
+
+	oldItems := itemCounts(oldResult)
+	newItems := itemCounts(newResult)
+	c.AddedItems = subtract(newItems, oldItems)
+	c.RemovedItems = subtract(oldItems, newItems)
+
+	if oldResult != nil {
+		c.OldCost = oldResult.CostSummary.Cost
+	}
+	if newResult != nil {
+		c.NewCost = newResult.CostSummary.Cost
+	}
+	c.CostDelta = c.NewCost - c.OldCost
+
+	return c
+}
+
+type itemCount struct {
+	ref   ItemRef
+	count int
+}
+
+// itemCounts возвращает количество каждого предмета в подарке.
+func itemCounts(r *domain.ChildResult) map[int]*itemCount {
+	counts := make(map[int]*itemCount)
+	if r == nil {
+		return counts
+	}
+	for _, item := range r.GiftSelection {
+		if _, ok := counts[item.ItemID]; !ok {
+			counts[item.ItemID] = &itemCount{ref: ItemRef{ItemID: item.ItemID, ItemName: item.ItemName}}
+		}
+		counts[item.ItemID].count++
+	}
+	return counts
+}
+
+// subtract возвращает предметы из a, которых нет в b (с учетом количества).
+func subtract(a, b map[int]*itemCount) []ItemRef {
+	var result []ItemRef
+	for id, c := range a {
+		n := c.count
+		if other, ok := b[id]; ok {
+			n -= other.count
+		}
+		for range n {
+			result = append(result, c.ref)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ItemID < result[j].ItemID })
+	return result
+}
+
+type groups map[string]*GroupDelta
+
+func newGroups() groups {
+	return make(groups)
+}
+
+func (g groups) add(name string, c ChildDiff) {
+	delta, ok := g[name]
+	if !ok {
+		delta = &GroupDelta{Name: name}
+		g[name] = delta
+	}
+	delta.Children++
+	delta.OldCost += c.OldCost
+	delta.NewCost += c.NewCost
+	delta.CostDelta = delta.NewCost - delta.OldCost
+}
+
+// list возвращает группы в заданном порядке, остальные - по алфавиту.
+func (g groups) list(order []string) []GroupDelta {
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i
+	}
+
+	result := make([]GroupDelta, 0, len(g))
+	for _, delta := range g {
+		result = append(result, *delta)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ri, iok := rank[result[i].Name]
+		rj, jok := rank[result[j].Name]
+		if iok && jok {
+			return ri < rj
+		}
+		if iok != jok {
+			return iok
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,225 @@
+package comparison
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"giftcalc/internal/application/calculation"
+	"giftcalc/internal/domain"
+)
+
+// Статусы изменения предмета каталога.
+const (
+	ItemAdded   = "added"
+	ItemRemoved = "removed"
+	ItemChanged = "changed"
+)
+
+// FieldChange - изменение одного поля метаданных предмета.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// ItemChange - изменение предмета между двумя версиями каталога.
+type ItemChange struct {
+	ItemID          int           `json:"item_id"`
+	ItemName        string        `json:"item_name"`
+	Status          string        `json:"status"`
+	OldPrice        domain.Money  `json:"old_price"`
+	NewPrice        domain.Money  `json:"new_price"`
+	PriceDelta      domain.Money  `json:"price_delta"`
+	MetadataChanges []FieldChange `json:"metadata_changes,omitempty"`
+}
+
+// SafetyAlert - предмет перестал соответствовать требованию, которое есть у детей.
+type SafetyAlert struct {
+	ItemID      int    `json:"item_id"`
+	ItemName    string `json:"item_name"`
+	Requirement string `json:"requirement"`
+	ChildIDs    []int  `json:"child_ids"`
+}
+
+// CatalogImpact - результат анализа изменения каталога.
+type CatalogImpact struct {
+	Items        []ItemChange  `json:"items"`
+	SafetyAlerts []SafetyAlert `json:"safety_alerts,omitempty"`
+
+	// Selection - изменения подарков детей и бюджета при пересчете с новым каталогом.
+	Selection ReportDiff `json:"selection"`
+}
+
+// Impact сравнивает каталоги и пересчитывает подарки с новым каталогом.
+// in.Catalog - старая версия каталога.
+func Impact(in calculation.Input, newCatalog []domain.CatalogItem, opts calculation.Options) CatalogImpact {
+	impact := CatalogImpact{
+		Items:        CompareCatalogs(in.Catalog, newCatalog),
+		SafetyAlerts: SafetyAlerts(in.Catalog, newCatalog, in.Children),
+	}
+
+	opts.Explain = false
+	oldReport := calculation.Run(in, opts)
+
+	in.Catalog = newCatalog
+	newReport := calculation.Run(in, opts)
+
+	impact.Selection = Compare(oldReport, newReport)
+	return impact
+}
+
+// CompareCatalogs возвращает добавленные, удаленные и измененные предметы.
+func CompareCatalogs(oldItems, newItems []domain.CatalogItem) []ItemChange {
+	oldByID := indexCatalog(oldItems)
+	newByID := indexCatalog(newItems)
+
+	var changes []ItemChange
+	for id, oldItem := range oldByID {
+		newItem, ok := newByID[id]
+		if !ok {
+			changes = append(changes, ItemChange{
+				ItemID:     id,
+				ItemName:   oldItem.Name,
+				Status:     ItemRemoved,
+				OldPrice:   oldItem.Price,
+				PriceDelta: -oldItem.Price,
+			})
+			continue
+		}
+
+		change := ItemChange{
+			ItemID:          id,
+			ItemName:        newItem.Name,
+			Status:          ItemChanged,
+			OldPrice:        oldItem.Price,
+			NewPrice:        newItem.Price,
+			PriceDelta:      newItem.Price - oldItem.Price,
+			MetadataChanges: metadataChanges(oldItem.Metadata, newItem.Metadata),
+		}
+		if change.PriceDelta != 0 || len(change.MetadataChanges) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	for id, newItem := range newByID {
+		if _, ok := oldByID[id]; !ok {
+			changes = append(changes, ItemChange{
+				ItemID:     id,
+				ItemName:   newItem.Name,
+				Status:     ItemAdded,
+				NewPrice:   newItem.Price,
+				PriceDelta: newItem.Price,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ItemID < changes[j].ItemID })
+	return changes
+}
+
+// SafetyAlerts находит предметы, которые перестали соответствовать требованиям
+// детей, например contains_nuts стал true для детей с nuts_allergy.
+func SafetyAlerts(oldItems, newItems []domain.CatalogItem, children []domain.Child) []SafetyAlert {
+	all := allRequirements()
+	newByID := indexCatalog(newItems)
+
+	var alerts []SafetyAlert
+	for _, oldCatalogItem := range oldItems {
+		newCatalogItem, ok := newByID[oldCatalogItem.Id]
+		if !ok {
+			continue
+		}
+
+		oldItem := oldCatalogItem.ToGiftItem()
+		newItem := newCatalogItem.ToGiftItem()
+		before := oldItem.GetComplianceSummary(all)
+		after := newItem.GetComplianceSummary(all)
+
+		var degraded []string
+		for key, ok := range before {
+			if ok && !after[key] {
+				degraded = append(degraded, key)
+			}
+		}
+		sort.Strings(degraded)
+
+		for _, key := range degraded {
+			alert := SafetyAlert{
+				ItemID:      newCatalogItem.Id,
+				ItemName:    newCatalogItem.Name,
+				Requirement: key,
+			}
+			for _, child := range children {
+				if _, has := newItem.GetComplianceSummary(child.SpecialRequirements)[key]; has {
+					alert.ChildIDs = append(alert.ChildIDs, child.ID)
+				}
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].ItemID < alerts[j].ItemID })
+	return alerts
+}
+
+func indexCatalog(items []domain.CatalogItem) map[int]domain.CatalogItem {
+	index := make(map[int]domain.CatalogItem, len(items))
+	for _, item := range items {
+		index[item.Id] = item
+	}
+	return index
+}
+
+// allRequirements возвращает требования со всеми известными значениями.
+func allRequirements() *domain.SpecialRequirements {
+	all := domain.GetAllRequirements()
+	reqs := &domain.SpecialRequirements{}
+	for _, v := range all["dietary"] {
+		reqs.Dietary = append(reqs.Dietary, domain.DietaryRequirement(v))
+	}
+	for _, v := range all["safety"] {
+		reqs.Safety = append(reqs.Safety, domain.SafetyRequirement(v))
+	}
+	for _, v := range all["medical"] {
+		reqs.Medical = append(reqs.Medical, domain.MedicalRequirement(v))
+	}
+	for _, v := range all["other"] {
+		reqs.Other = append(reqs.Other, domain.OtherRequirement(v))
+	}
+	return reqs
+}
+
+// metadataChanges сравнивает метаданные по полям, поля называются по JSON тегам.
+func metadataChanges(oldMeta, newMeta domain.Metadata) []FieldChange {
+	var changes []FieldChange
+
+	ov := reflect.ValueOf(oldMeta)
+	nv := reflect.ValueOf(newMeta)
+	t := ov.Type()
+	for i := range t.NumField() {
+		o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if reflect.DeepEqual(o, n) || (isEmpty(ov.Field(i)) && isEmpty(nv.Field(i))) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		changes = append(changes, FieldChange{Field: name, Old: o, New: n})
+	}
+
+	return changes
+}
+
+// isEmpty считает nil и пустой срез одинаковыми.
+func isEmpty(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Len() == 0
+}
+
+// String возвращает краткое описание изменения поля.
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Field, c.Old, c.New)
+}
@@ -0,0 +1,157 @@
+package logistics
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// Plan раскладывает готовые подарки по рейсам. Коробки группируются по
+// регионам, регионы идут в порядке маршрута (Region.Order), внутри региона
+// коробки укладываются в рейсы методом First Fit Decreasing с учетом
+// вместимости по весу и объему. Рейсы не смешивают регионы.
+// Для подарков с назначенной коробкой (ChildResult.Box) объем берется из boxes.
+func Plan(results []domain.ChildResult, catalog []domain.CatalogItem, regions []domain.Region, boxes []domain.BoxSize, rules domain.LogisticsRules) domain.LoadManifest {
+	volumes := make(map[int]float64, len(catalog))
+	for _, item := range catalog {
+		volumes[item.Id] = item.PackedVolume()
+	}
+	boxVolumes := make(map[string]float64, len(boxes))
+	for _, b := range boxes {
+		boxVolumes[b.Name] = b.Inner.Volume()
+	}
+
+	byRegion := make(map[string][]domain.ChildBox)
+	for _, r := range results {
+		if len(r.GiftSelection) == 0 {
+			continue
+		}
+		byRegion[r.Region] = append(byRegion[r.Region], childBox(r, volumes, boxVolumes, rules.BoxVolume))
+	}
+
+	manifest := domain.LoadManifest{Vehicle: rules.Vehicle}
+	prev := rules.Depot
+	for i, region := range route(byRegion, regions) {
+		stop := domain.RouteStop{Order: i + 1, Region: region.Name, Location: region.Location}
+		if prev != nil && region.Location != nil {
+			stop.Distance = prev.DistanceTo(*region.Location)
+			manifest.Distance += stop.Distance
+		}
+		if region.Location != nil {
+			prev = region.Location
+		}
+
+		loads, oversized := pack(byRegion[region.Name], rules.Vehicle)
+		for _, load := range loads {
+			load.Number = len(manifest.Loads) + 1
+			load.Region = region.Name
+			manifest.Loads = append(manifest.Loads, load)
+		}
+		manifest.Oversized = append(manifest.Oversized, oversized...)
+
+		stop.Loads = len(loads)
+		manifest.Route = append(manifest.Route, stop)
+	}
+
+	return manifest
+}
+
+// childBox собирает коробку ребенка. Объем коробки - объем назначенной
+// коробки, а если она не назначена или неизвестна - сумма объемов предметов,
+// но не меньше минимального объема коробки.
+func childBox(r domain.ChildResult, volumes map[int]float64, boxVolumes map[string]float64, minVolume float64) domain.ChildBox {
+	box := domain.ChildBox{ChildID: r.ChildID, ChildName: r.ChildName, Region: r.Region}
+	for _, item := range r.GiftSelection {
+		box.Weight += item.Weight
+		box.Volume += volumes[item.ItemID]
+	}
+	if v, ok := boxVolumes[r.Box]; ok {
+		box.Volume = v
+		return box
+	}
+	box.Volume = max(box.Volume, minVolume)
+	return box
+}
+
+// route возвращает регионы с подарками в порядке маршрута: сначала регионы
+// с заданным порядком, затем остальные регионы файла в порядке файла, затем
+// регионы, отсутствующие в файле, по алфавиту.
+func route(byRegion map[string][]domain.ChildBox, regions []domain.Region) []domain.Region {
+	known := make(map[string]bool, len(regions))
+	var ordered []domain.Region
+	for _, r := range regions {
+		known[r.Name] = true
+		if len(byRegion[r.Name]) > 0 {
+			ordered = append(ordered, r)
+		}
+	}
+	slices.SortStableFunc(ordered, func(a, b domain.Region) int {
+		switch {
+		case a.Order == 0 && b.Order == 0:
+			return 0
+		case a.Order == 0:
+			return 1
+		case b.Order == 0:
+			return -1
+		}
+		return cmp.Compare(a.Order, b.Order)
+	})
+
+	var unknown []string
+	for name := range byRegion {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		ordered = append(ordered, domain.Region{Name: name})
+	}
+
+	return ordered
+}
+
+// pack укладывает коробки в рейсы методом First Fit Decreasing: коробки
+// сортируются по наибольшей доле вместимости (вес или объем) и кладутся в
+// первый рейс, где для них есть место. Коробки больше вместимости рейса
+// возвращаются отдельно.
+func pack(boxes []domain.ChildBox, capacity domain.LoadCapacity) ([]domain.Load, []domain.ChildBox) {
+	share := func(b domain.ChildBox) float64 {
+		return max(b.Weight/capacity.MaxWeight, b.Volume/capacity.MaxVolume)
+	}
+
+	sorted := slices.Clone(boxes)
+	slices.SortStableFunc(sorted, func(a, b domain.ChildBox) int {
+		return cmp.Compare(share(b), share(a))
+	})
+
+	var loads []domain.Load
+	var oversized []domain.ChildBox
+	for _, box := range sorted {
+		if box.Weight > capacity.MaxWeight || box.Volume > capacity.MaxVolume {
+			oversized = append(oversized, box)
+			continue
+		}
+
+		placed := false
+		for i := range loads {
+			if loads[i].Weight+box.Weight <= capacity.MaxWeight && loads[i].Volume+box.Volume <= capacity.MaxVolume {
+				loads[i].Add(box)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			loads = append(loads, domain.Load{})
+			loads[len(loads)-1].Add(box)
+		}
+	}
+
+	for i := range loads {
+		loads[i].WeightUtilization = loads[i].Weight / capacity.MaxWeight
+		loads[i].VolumeUtilization = loads[i].Volume / capacity.MaxVolume
+	}
+	return loads, oversized
+}
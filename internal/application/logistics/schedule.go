@@ -0,0 +1,113 @@
+package logistics
+
+import (
+	"slices"
+	"time"
+
+	"giftcalc/internal/domain"
+)
+
+// Schedule строит расписание доставки рейсов плана загрузки. Регионы
+// обслуживаются в порядке наступления Нового года (раньше всех - восточные),
+// каждый рейс уходит на первые освободившиеся сани. Сани возвращаются на склад
+// тем же путем. Рейс опаздывает, если прибывает позже полуночи 31 декабря
+// по местному времени региона.
+func Schedule(manifest domain.LoadManifest, regions []domain.Region, depot *domain.Coordinates, rules domain.ScheduleRules, year int) (domain.DeliverySchedule, error) {
+	fallback, err := time.LoadLocation(firstZone(rules.TimeZone))
+	if err != nil {
+		return domain.DeliverySchedule{}, err
+	}
+
+	byName := make(map[string]domain.Region, len(regions))
+	for _, r := range regions {
+		byName[r.Name] = r
+	}
+
+	type stop struct {
+		region   domain.Region
+		zone     *time.Location
+		deadline time.Time
+		travel   time.Duration
+	}
+
+	stops := make([]stop, 0, len(manifest.Route))
+	for _, s := range manifest.Route {
+		region, ok := byName[s.Region]
+		if !ok {
+			region = domain.Region{Name: s.Region, Location: s.Location}
+		}
+		zone, err := region.Zone(fallback)
+		if err != nil {
+			return domain.DeliverySchedule{}, err
+		}
+		deadline, _ := region.NewYear(year, fallback)
+		stops = append(stops, stop{
+			region:   region,
+			zone:     zone,
+			deadline: deadline,
+			travel:   rules.TravelTime(depot, s.Location),
+		})
+	}
+	slices.SortStableFunc(stops, func(a, b stop) int { return a.deadline.Compare(b.deadline) })
+
+	free := make([]time.Time, max(rules.Vehicles, 1))
+	for i := range free {
+		free[i] = rules.Start
+	}
+	loading := time.Duration(rules.LoadingMinutes) * time.Minute
+
+	schedule := domain.DeliverySchedule{Start: rules.Start}
+	for _, s := range stops {
+		summary := domain.RegionSchedule{
+			Region:   s.region.Name,
+			TimeZone: s.zone.String(),
+			Deadline: s.deadline,
+		}
+
+		for _, load := range manifest.Loads {
+			if load.Region != s.region.Name {
+				continue
+			}
+
+			vehicle := 0
+			for i := range free {
+				if free[i].Before(free[vehicle]) {
+					vehicle = i
+				}
+			}
+
+			departure := free[vehicle].Add(loading)
+			arrival := departure.Add(s.travel)
+			free[vehicle] = arrival.Add(s.travel)
+
+			scheduled := domain.ScheduledLoad{
+				Number:        load.Number,
+				Region:        load.Region,
+				Vehicle:       vehicle + 1,
+				DepartureFrom: departure.In(s.zone),
+				DepartureBy:   s.deadline.Add(-s.travel),
+				Arrival:       arrival.In(s.zone),
+				Deadline:      s.deadline,
+				Late:          arrival.After(s.deadline),
+			}
+			schedule.Loads = append(schedule.Loads, scheduled)
+
+			summary.Loads++
+			summary.Late = summary.Late || scheduled.Late
+			if scheduled.Arrival.After(summary.LastArrival) {
+				summary.LastArrival = scheduled.Arrival
+			}
+		}
+
+		schedule.Regions = append(schedule.Regions, summary)
+	}
+
+	return schedule, nil
+}
+
+func firstZone(zone string) string {
+	if zone == "" {
+		return domain.DefaultTimeZone
+	}
+	return zone
+}
@@ -0,0 +1,179 @@
+package notes
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"giftcalc/internal/domain"
+)
+
+// inexactPenalty - доля уверенности, теряемая, если слова совпали только по основе.
+const inexactPenalty = 0.15
+
+// negationWindow - сколько слов перед фразой проверяется на отрицание.
+const negationWindow = 2
+
+// Proposal - предложение по заметке ребенка, требующее проверки человеком.
+type Proposal struct {
+	ChildID   int    `json:"child_id"`
+	ChildName string `json:"child_name"`
+
+	// Fragment - фрагмент заметки, на котором сработал шаблон.
+	Fragment string `json:"fragment"`
+	Pattern  string `json:"pattern"`
+
+	// Category и Value - предлагаемое требование (см. SpecialRequirements.HasRequirement).
+	Category string `json:"category,omitempty"`
+	Value    string `json:"value,omitempty"`
+
+	// Ban и BanCategory - запрет по слову в названии или по категории,
+	// ItemIDs - предметы каталога, которые под него попадают.
+	Ban         string `json:"ban,omitempty"`
+	BanCategory string `json:"ban_category,omitempty"`
+	ItemIDs     []int  `json:"item_ids,omitempty"`
+
+	Confidence float64 `json:"confidence"`
+
+	// Existing - требование уже указано у ребенка.
+	Existing bool `json:"existing,omitempty"`
+}
+
+// Extract разбирает заметки ребенка по словарю.
+// Совпадения, перед которыми стоит отрицание ("нет аллергии на орехи"), пропускаются.
+func Extract(child domain.Child, dict *domain.NotesDictionary, catalog []domain.CatalogItem) []Proposal {
+	if strings.TrimSpace(child.Notes) == "" || dict == nil {
+		return nil
+	}
+
+	tokens := domain.Tokenize(child.Notes)
+	stems := make([]string, len(tokens))
+	for i, t := range tokens {
+		stems[i] = domain.Stem(t)
+	}
+
+	negations := make(map[string]bool, len(dict.Negations))
+	for _, n := range dict.Negations {
+		negations[strings.ToLower(n)] = true
+	}
+
+	best := make(map[string]Proposal)
+	for _, pattern := range dict.Patterns {
+		phrase := domain.Tokenize(pattern.Phrase)
+		phraseStems := domain.Stems(pattern.Phrase)
+		if len(phrase) == 0 {
+			continue
+		}
+
+		for start := range stems {
+			end, exact, ok := match(tokens, stems, start, phrase, phraseStems, dict.MaxGap)
+			if !ok || negated(tokens, start, negations, phrase) {
+				continue
+			}
+
+			p := Proposal{
+				ChildID:     child.ID,
+				ChildName:   child.Name,
+				Fragment:    strings.Join(tokens[start:end+1], " "),
+				Pattern:     pattern.Phrase,
+				Ban:         pattern.Ban,
+				BanCategory: pattern.BanCategory,
+				Confidence:  pattern.Confidence * (1 - inexactPenalty*float64(len(phrase)-exact)/float64(len(phrase))),
+			}
+			p.Category, p.Value = pattern.Requirement()
+			if p.Category != "" {
+				p.Existing = child.SpecialRequirements.HasRequirement(p.Category, p.Value)
+			}
+			if p.Ban != "" || p.BanCategory != "" {
+				p.ItemIDs = bannedItems(catalog, p.Ban, p.BanCategory)
+			}
+
+			key := strings.Join([]string{p.Category, p.Value, p.Ban, p.BanCategory}, ":")
+			if current, ok := best[key]; !ok || p.Confidence > current.Confidence {
+				best[key] = p
+			}
+		}
+	}
+
+	result := make([]Proposal, 0, len(best))
+	for _, p := range best {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Confidence != result[j].Confidence {
+			return result[i].Confidence > result[j].Confidence
+		}
+		return result[i].Pattern < result[j].Pattern
+	})
+	return result
+}
+
+// match ищет фразу, начиная с позиции start. Возвращает позицию последнего
+// совпавшего слова и количество слов, совпавших точно, а не только по основе.
+func match(tokens, stems []string, start int, phrase, phraseStems []string, maxGap int) (int, int, bool) {
+	if stems[start] != phraseStems[0] {
+		return 0, 0, false
+	}
+
+	exact := 0
+	if tokens[start] == phrase[0] {
+		exact++
+	}
+
+	pos := start
+	for k := 1; k < len(phraseStems); k++ {
+		found := false
+		for next := pos + 1; next < len(stems) && next <= pos+1+maxGap; next++ {
+			if stems[next] == phraseStems[k] {
+				if tokens[next] == phrase[k] {
+					exact++
+				}
+				pos, found = next, true
+				break
+			}
+		}
+		if !found {
+			return 0, 0, false
+		}
+	}
+
+	return pos, exact, true
+}
+
+// negated проверяет, стоит ли перед фразой слово отрицания.
+// Шаблоны, которые сами начинаются с отрицания ("не ест сладкое"), не проверяются.
+func negated(tokens []string, start int, negations map[string]bool, phrase []string) bool {
+	if negations[phrase[0]] {
+		return false
+	}
+	for i := max(0, start-negationWindow); i < start; i++ {
+		if negations[tokens[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// bannedItems возвращает предметы запрещенной категории или с запрещенным словом в названии.
+func bannedItems(catalog []domain.CatalogItem, ban, category string) []int {
+	banStems := domain.Stems(ban)
+
+	var ids []int
+	for _, item := range catalog {
+		if item.Category == category || (len(banStems) > 0 && containsAll(domain.Stems(item.Name), banStems)) {
+			ids = append(ids, item.Id)
+		}
+	}
+	return ids
+}
+
+// containsAll проверяет, что для каждой основы есть слово, которое с нее начинается:
+// "шоколад" находит "шоколадная".
+func containsAll(words, stems []string) bool {
+	for _, stem := range stems {
+		if !slices.ContainsFunc(words, func(w string) bool { return strings.HasPrefix(w, stem) }) {
+			return false
+		}
+	}
+	return true
+}
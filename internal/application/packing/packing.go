@@ -0,0 +1,110 @@
+package packing
+
+import (
+	"cmp"
+	"slices"
+
+	"giftcalc/internal/domain"
+)
+
+// SmallestBox возвращает наименьшую по объему коробку, в которую помещаются
+// предметы подарка. Вторым значением возвращается false, если подходящей
+// коробки нет. Предметы без габаритов не учитываются.
+func SmallestBox(items []domain.Dimensions, weight float64, boxes []domain.BoxSize) (domain.BoxSize, bool) {
+	var volume float64
+	for _, d := range items {
+		volume += d.Volume()
+	}
+
+	sorted := slices.Clone(boxes)
+	slices.SortStableFunc(sorted, func(a, b domain.BoxSize) int {
+		return cmp.Compare(a.Inner.Volume(), b.Inner.Volume())
+	})
+
+	for _, box := range sorted {
+		if box.MaxWeight > 0 && weight > box.MaxWeight {
+			continue
+		}
+		if volume > box.Inner.Volume() {
+			continue
+		}
+		if Fits(items, box.Inner) {
+			return box, true
+		}
+	}
+	return domain.BoxSize{}, false
+}
+
+// placement - предмет, уложенный в коробку: угол и габариты в выбранной ориентации.
+type placement struct {
+	x, y, z float64
+	size    domain.Dimensions
+}
+
+func (p placement) overlaps(q placement) bool {
+	return p.x < q.x+q.size.Length && q.x < p.x+p.size.Length &&
+		p.y < q.y+q.size.Width && q.y < p.y+p.size.Width &&
+		p.z < q.z+q.size.Height && q.z < p.z+p.size.Height
+}
+
+// Fits проверяет, помещаются ли предметы в коробку, эвристикой крайних точек
+// (extreme points): предметы укладываются по убыванию объема, каждый - в
+// первую крайнюю точку (снизу вверх, от дальней стенки), где он помещается
+// в одной из шести ориентаций. Эвристика может не найти укладку, которая
+// существует, но найденная укладка всегда корректна.
+func Fits(items []domain.Dimensions, box domain.Dimensions) bool {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b domain.Dimensions) int {
+		return cmp.Compare(b.Volume(), a.Volume())
+	})
+
+	var placed []placement
+	points := []placement{{}}
+
+	for _, item := range sorted {
+		ok := false
+		for i, point := range points {
+			for _, size := range item.Rotations() {
+				candidate := placement{x: point.x, y: point.y, z: point.z, size: size}
+				if !inside(candidate, box) || overlapsAny(candidate, placed) {
+					continue
+				}
+
+				placed = append(placed, candidate)
+				points = append(points[:i:i], points[i+1:]...)
+				points = append(points,
+					placement{x: candidate.x + size.Length, y: candidate.y, z: candidate.z},
+					placement{x: candidate.x, y: candidate.y + size.Width, z: candidate.z},
+					placement{x: candidate.x, y: candidate.y, z: candidate.z + size.Height},
+				)
+				slices.SortStableFunc(points, func(a, b placement) int {
+					return cmp.Or(cmp.Compare(a.z, b.z), cmp.Compare(a.y, b.y), cmp.Compare(a.x, b.x))
+				})
+				ok = true
+				break
+			}
+			if ok {
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func inside(p placement, box domain.Dimensions) bool {
+	return p.x+p.size.Length <= box.Length &&
+		p.y+p.size.Width <= box.Width &&
+		p.z+p.size.Height <= box.Height
+}
+
+func overlapsAny(p placement, placed []placement) bool {
+	for _, q := range placed {
+		if p.overlaps(q) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package packing
+
+import (
+	"testing"
+
+	"giftcalc/internal/domain"
+)
+
+func dims(l, w, h float64) domain.Dimensions {
+	return domain.Dimensions{Length: l, Width: w, Height: h}
+}
+
+// TestFits проверяет укладку предметов в коробку.
+func TestFits(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []domain.Dimensions
+		box   domain.Dimensions
+		want  bool
+	}{
+		{"без предметов", nil, dims(10, 10, 10), true},
+		{"как есть", []domain.Dimensions{dims(10, 5, 5)}, dims(10, 10, 10), true},
+		{"только с поворотом", []domain.Dimensions{dims(10, 30, 5)}, dims(30, 10, 10), true},
+		{"поворот на бок", []domain.Dimensions{dims(5, 5, 25)}, dims(30, 10, 10), true},
+		{"два предмета рядом", []domain.Dimensions{dims(15, 15, 15), dims(15, 15, 15)}, dims(30, 20, 15), true},
+		{"по объему, но не по длине", []domain.Dimensions{dims(35, 5, 5)}, dims(20, 15, 10), false},
+		{"по объему, но не по форме", []domain.Dimensions{dims(15, 15, 15), dims(15, 15, 15)}, dims(20, 20, 20), false},
+		{"больше коробки", []domain.Dimensions{dims(30, 30, 30)}, dims(20, 20, 20), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fits(tt.items, tt.box); got != tt.want {
+				t.Errorf("Fits = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSmallestBox проверяет выбор наименьшей подходящей стандартной коробки.
+func TestSmallestBox(t *testing.T) {
+	tests := []struct {
+		name   string
+		items  []domain.Dimensions
+		weight float64
+		want   string
+		ok     bool
+	}{
+		{"пустой подарок", nil, 0, "S", true},
+		{"помещается в S", []domain.Dimensions{dims(10, 10, 5)}, 0.5, "S", true},
+		{"S только с поворотом", []domain.Dimensions{dims(10, 20, 15)}, 0.5, "S", true},
+		{"по объему S, по длине L", []domain.Dimensions{dims(35, 5, 5)}, 0.5, "L", true},
+		{"по весу M", []domain.Dimensions{dims(10, 10, 5)}, 3, "M", true},
+		{"не помещается никуда", []domain.Dimensions{dims(70, 10, 10)}, 1, "", false},
+		{"слишком тяжелый", []domain.Dimensions{dims(10, 10, 5)}, 25, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box, ok := SmallestBox(tt.items, tt.weight, domain.StandardBoxes)
+			if ok != tt.ok || box.Name != tt.want {
+				t.Errorf("SmallestBox = %q, %v, ожидалось %q, %v", box.Name, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
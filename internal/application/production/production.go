@@ -0,0 +1,101 @@
+package production
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// Plan составляет производственный план по отчету: сколько предметов каждого
+// вида изготовить, разбивку по категориям и сколько коробок каждого размера
+// заказать. Общие предметы семей входят в подарки детей, которые их везут.
+// categories - названия категорий каталога по идентификатору.
+func Plan(report domain.Report, categories map[string]string, boxes []domain.BoxSize) domain.ProductionSummary {
+	var summary domain.ProductionSummary
+
+	items := make(map[int]*domain.ProductionItemBreakdown)
+	add := func(s domain.GiftSelection) {
+		item, ok := items[s.ItemID]
+		if !ok {
+			item = &domain.ProductionItemBreakdown{ItemID: s.ItemID, ItemName: s.ItemName, Category: s.Category}
+			items[s.ItemID] = item
+		}
+		item.RequiredQuantity++
+		item.TotalCost += s.Price
+		item.TotalWeight += s.Weight
+		summary.TotalItemsNeeded++
+	}
+
+	boxCounts := make(map[string]int)
+	for _, r := range report.Results {
+		for _, s := range r.GiftSelection {
+			add(s)
+		}
+		if len(r.GiftSelection) == 0 {
+			continue
+		}
+		if r.Box == "" {
+			summary.Unboxed = append(summary.Unboxed, r.ChildID)
+			continue
+		}
+		boxCounts[r.Box]++
+	}
+
+	summary.ItemsBreakdown = itemsBreakdown(items)
+	summary.CategoriesBreakdown = categoriesBreakdown(summary.ItemsBreakdown, categories)
+	summary.BoxesBreakdown = boxesBreakdown(boxCounts, boxes)
+	return summary
+}
+
+func itemsBreakdown(items map[int]*domain.ProductionItemBreakdown) []domain.ProductionItemBreakdown {
+	result := make([]domain.ProductionItemBreakdown, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ItemID < result[j].ItemID })
+	return result
+}
+
+func categoriesBreakdown(items []domain.ProductionItemBreakdown, names map[string]string) []domain.ProductionCategoryBreakdown {
+	index := make(map[string]int)
+	var result []domain.ProductionCategoryBreakdown
+	for _, item := range items {
+		i, ok := index[item.Category]
+		if !ok {
+			i = len(result)
+			index[item.Category] = i
+			result = append(result, domain.ProductionCategoryBreakdown{
+				CategoryID:   item.Category,
+				CategoryName: names[item.Category],
+			})
+		}
+		result[i].ItemsCount++
+		result[i].TotalQuantity += item.RequiredQuantity
+		result[i].TotalCost += item.TotalCost
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CategoryID < result[j].CategoryID })
+	return result
+}
+
+// boxesBreakdown перечисляет коробки в порядке настроек; коробки из отчета,
+// которых нет в настройках, идут в конце по названию.
+func boxesBreakdown(counts map[string]int, boxes []domain.BoxSize) []domain.ProductionBoxBreakdown {
+	var result []domain.ProductionBoxBreakdown
+	for _, b := range boxes {
+		if counts[b.Name] == 0 {
+			continue
+		}
+		result = append(result, domain.ProductionBoxBreakdown{Box: b.Name, Dimensions: b.Inner, Quantity: counts[b.Name]})
+		delete(counts, b.Name)
+	}
+
+	unknown := make([]string, 0, len(counts))
+	for name := range counts {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		result = append(result, domain.ProductionBoxBreakdown{Box: name, Quantity: counts[name]})
+	}
+	return result
+}
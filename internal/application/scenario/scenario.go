@@ -0,0 +1,107 @@
+package scenario
+
+import (
+	"fmt"
+
+	"giftcalc/internal/application/calculation"
+	"giftcalc/internal/application/comparison"
+	"giftcalc/internal/domain"
+)
+
+// CurrentCoefficients - название таблицы коэффициентов из текущих настроек.
+const CurrentCoefficients = "текущие"
+
+// Outcome - итоги расчета одного сценария.
+type Outcome struct {
+	Name          string       `json:"name"`
+	MaxBudget     domain.Money `json:"max_budget"`
+	MaxCount      int          `json:"max_count"`
+	Coefficients  string       `json:"coefficients"`
+	TotalCost     domain.Money `json:"total_cost"`
+	FullyServed   int          `json:"fully_served"`
+	Failed        int          `json:"failed"`
+	AverageItems  float64      `json:"average_items"`
+	ChildrenCount int          `json:"children_count"`
+}
+
+// Run выполняет расчет для каждого сценария сетки.
+// Параметры, не заданные в сетке, берутся из base.
+func Run(in calculation.Input, base calculation.Options, grid domain.ScenarioGrid) []Outcome {
+	budgets := grid.MaxBudgets
+	if len(budgets) == 0 {
+		budgets = []domain.Money{base.MaxBudget}
+	}
+
+	counts := grid.MaxCounts
+	if len(counts) == 0 {
+		counts = []int{base.MaxCount}
+	}
+
+	tables := make([]namedCoefficients, 0, len(grid.Coefficients))
+	for _, t := range grid.Coefficients {
+		tables = append(tables, namedCoefficients{name: t.Name, values: coefficients(t.Regions)})
+	}
+	if len(tables) == 0 {
+		tables = append(tables, namedCoefficients{name: CurrentCoefficients, values: base.Coefficients})
+	}
+
+	outcomes := make([]Outcome, 0, len(budgets)*len(counts)*len(tables))
+	for _, budget := range budgets {
+		for _, count := range counts {
+			for _, table := range tables {
+				opts := base
+				opts.MaxBudget = budget
+				opts.MaxCount = count
+				opts.Coefficients = table.values
+				opts.Explain = false
+
+				outcome := summarize(calculation.Run(in, opts))
+				outcome.Name = fmt.Sprintf("бюджет %.0f, позиций %d, коэффициенты %s", budget.Float(), count, table.name)
+				outcome.MaxBudget = budget
+				outcome.MaxCount = count
+				outcome.Coefficients = table.name
+				outcomes = append(outcomes, outcome)
+			}
+		}
+	}
+
+	return outcomes
+}
+
+type namedCoefficients struct {
+	name   string
+	values map[string]float64
+}
+
+func coefficients(regions []domain.Region) map[string]float64 {
+	result := make(map[string]float64, len(regions))
+	for _, r := range regions {
+		result[r.Name] = r.Coefficient
+	}
+	return result
+}
+
+// summarize считает итоги отчета. Ребенок обслужен полностью, если подарок
+// подобран без ошибок и предупреждений.
+func summarize(report domain.Report) Outcome {
+	outcome := Outcome{ChildrenCount: len(report.Results)}
+
+	items := 0
+	for i := range report.Results {
+		r := &report.Results[i]
+		outcome.TotalCost += r.CostSummary.Cost
+		items += len(r.GiftSelection)
+
+		switch {
+		case comparison.Failing(r):
+			outcome.Failed++
+		case len(r.Warnings) == 0:
+			outcome.FullyServed++
+		}
+	}
+
+	if outcome.ChildrenCount > 0 {
+		outcome.AverageItems = float64(items) / float64(outcome.ChildrenCount)
+	}
+	return outcome
+}
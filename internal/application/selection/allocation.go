@@ -0,0 +1,120 @@
+package selection
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// WishIndex содержит пожелания, сгруппированные по ID ребенка.
+type WishIndex map[int][]domain.Wish
+
+// IndexWishes группирует пожелания по детям.
+func IndexWishes(wishes []domain.Wish) WishIndex {
+	index := make(WishIndex)
+	for _, w := range wishes {
+		index[w.ChildID] = append(index[w.ChildID], w)
+	}
+	return index
+}
+
+// priorityRank возвращает порядковый номер приоритета: чем меньше, тем важнее.
+func priorityRank(p domain.WishPriority) int {
+	switch p {
+	case domain.PriorityHigh:
+		return 0
+	case domain.PriorityMedium:
+		return 1
+	case domain.PriorityLow:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// rank возвращает наивысший приоритет пожеланий ребенка.
+func (w WishIndex) rank(childID int) int {
+	best := priorityRank("")
+	for _, wish := range w[childID] {
+		best = min(best, priorityRank(wish.Priority))
+	}
+	return best
+}
+
+// AllocationOrder возвращает порядок обработки детей (индексы во входном срезе)
+// при распределении запаса. Сначала дети с более приоритетными пожеланиями,
+// внутри одного приоритета регионы обслуживаются по очереди, чтобы дефицитные
+// предметы не ушли целиком первому региону в файле.
+func AllocationOrder(children []domain.Child, wishes WishIndex) []int {
+	tiers := make(map[int][]int)
+	for i, child := range children {
+		r := wishes.rank(child.ID)
+		tiers[r] = append(tiers[r], i)
+	}
+
+	ranks := make([]int, 0, len(tiers))
+	for r := range tiers {
+		ranks = append(ranks, r)
+	}
+	sort.Ints(ranks)
+
+	result := make([]int, 0, len(children))
+	for _, r := range ranks {
+		result = append(result, roundRobinByRegion(children, tiers[r])...)
+	}
+	return result
+}
+
+// roundRobinByRegion чередует детей разных регионов, сохраняя порядок внутри региона.
+func roundRobinByRegion(children []domain.Child, indices []int) []int {
+	var regions []string
+	queues := make(map[string][]int)
+	for _, i := range indices {
+		region := children[i].Region
+		if _, ok := queues[region]; !ok {
+			regions = append(regions, region)
+		}
+		queues[region] = append(queues[region], i)
+	}
+
+	result := make([]int, 0, len(indices))
+	for len(result) < len(indices) {
+		for _, region := range regions {
+			if q := queues[region]; len(q) > 0 {
+				result = append(result, q[0])
+				queues[region] = q[1:]
+			}
+		}
+	}
+	return result
+}
+
+// PreferWished переставляет желанные предметы ребенка в начало каталога
+// в порядке приоритета пожеланий. Остальные предметы сохраняют свой порядок.
+func (w WishIndex) PreferWished(childID int, items []domain.CatalogItem) []domain.CatalogItem {
+	childWishes := w[childID]
+	if len(childWishes) == 0 {
+		return items
+	}
+
+	wished := make(map[int]int)
+	for _, wish := range childWishes {
+		for _, id := range wish.ItemIDs {
+			r := priorityRank(wish.Priority)
+			if current, ok := wished[id]; !ok || r < current {
+				wished[id] = r
+			}
+		}
+	}
+
+	ordered := append([]domain.CatalogItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, iw := wished[ordered[i].Id]
+		rj, jw := wished[ordered[j].Id]
+		if iw != jw {
+			return iw
+		}
+		return iw && ri < rj
+	})
+	return ordered
+}
@@ -0,0 +1,131 @@
+package selection
+
+import (
+	"slices"
+	"testing"
+
+	"giftcalc/internal/domain"
+)
+
+// TestAllocationOrder проверяет порядок распределения запаса: сначала
+// приоритетные пожелания, внутри приоритета регионы по очереди.
+func TestAllocationOrder(t *testing.T) {
+	children := []domain.Child{
+		{ID: 1, Name: "Аня", Age: 8, Region: "Москва"},
+		{ID: 2, Name: "Борис", Age: 8, Region: "Москва"},
+		{ID: 3, Name: "Вера", Age: 8, Region: "Якутск"},
+		{ID: 4, Name: "Глеб", Age: 8, Region: "Якутск"},
+		{ID: 5, Name: "Дина", Age: 8, Region: "Москва"},
+	}
+
+	tests := []struct {
+		name   string
+		wishes []domain.Wish
+		order  []int
+	}{
+		{
+			name:  "без пожеланий регионы чередуются",
+			order: []int{0, 2, 1, 3, 4},
+		},
+		{
+			name: "приоритеты, затем регионы",
+			wishes: []domain.Wish{
+				{ChildID: 2, ItemIDs: []int{101}, Priority: domain.PriorityLow},
+				{ChildID: 3, ItemIDs: []int{101}, Priority: domain.PriorityHigh},
+				{ChildID: 5, ItemIDs: []int{101}, Priority: domain.PriorityHigh},
+			},
+			order: []int{2, 4, 1, 0, 3},
+		},
+		{
+			name: "берется наивысший приоритет ребенка",
+			wishes: []domain.Wish{
+				{ChildID: 1, ItemIDs: []int{101}, Priority: domain.PriorityLow},
+				{ChildID: 1, ItemIDs: []int{102}, Priority: domain.PriorityMedium},
+				{ChildID: 4, ItemIDs: []int{101}, Priority: domain.PriorityLow},
+			},
+			order: []int{0, 3, 1, 2, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AllocationOrder(children, IndexWishes(tt.wishes))
+			if !slices.Equal(got, tt.order) {
+				t.Errorf("порядок %v, ожидалось %v", got, tt.order)
+			}
+		})
+	}
+}
+
+// TestAllocationShortage проверяет, кому достаются последние предметы на складе
+// и у кого фиксируется нехватка.
+func TestAllocationShortage(t *testing.T) {
+	children := []domain.Child{
+		{ID: 1, Name: "Аня", Age: 8, Region: "Москва"},
+		{ID: 2, Name: "Борис", Age: 8, Region: "Москва"},
+		{ID: 3, Name: "Вера", Age: 8, Region: "Якутск"},
+	}
+
+	tests := []struct {
+		name     string
+		stock    int
+		wishes   []domain.Wish
+		winners  []int
+		shortage []int
+	}{
+		{
+			name:     "без пожеланий - первый в порядке файла",
+			stock:    1,
+			winners:  []int{1},
+			shortage: []int{2, 3},
+		},
+		{
+			name:  "высокий приоритет важнее порядка файла",
+			stock: 1,
+			wishes: []domain.Wish{
+				{ChildID: 1, ItemIDs: []int{101}, Priority: domain.PriorityLow},
+				{ChildID: 3, ItemIDs: []int{101}, Priority: domain.PriorityHigh},
+			},
+			winners:  []int{3},
+			shortage: []int{1, 2},
+		},
+		{
+			name:     "второй регион не остается без предмета",
+			stock:    2,
+			winners:  []int{1, 3},
+			shortage: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := []domain.CatalogItem{
+				{Id: 101, Name: "Пряник", Category: "sweets", Price: 10000, Weight: 0.2, Stock: &tt.stock},
+			}
+			stock := NewStock(catalog)
+			wishes := IndexWishes(tt.wishes)
+			params := Params{MaxCount: 1, MaxBudget: 100000, Stock: stock}
+
+			var winners, shortage []int
+			for _, idx := range AllocationOrder(children, wishes) {
+				child := children[idx]
+				res := Select(child, wishes.PreferWished(child.ID, catalog), params)
+				if len(res.Items) > 0 {
+					winners = append(winners, child.ID)
+				}
+				if len(res.Shortages) > 0 {
+					shortage = append(shortage, child.ID)
+				}
+			}
+
+			slices.Sort(winners)
+			slices.Sort(shortage)
+			if !slices.Equal(winners, tt.winners) {
+				t.Errorf("предмет получили %v, ожидались %v", winners, tt.winners)
+			}
+			if !slices.Equal(shortage, tt.shortage) {
+				t.Errorf("нехватка у %v, ожидалась у %v", shortage, tt.shortage)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package selection
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// PreferFresh переставляет в конец каталога предметы, которые ребенок уже
+// получал, если для них задана политика penalize. Сначала идут новые предметы,
+// затем предметы из уже знакомых категорий, затем повторы тех же предметов.
+func PreferFresh(items []domain.CatalogItem, rules *domain.HistoryRules, past domain.PastGifts) []domain.CatalogItem {
+	if rules == nil || past.Empty() {
+		return items
+	}
+
+	rank := func(item domain.CatalogItem) int {
+		policy, _ := rules.Repeats(past, item.Id, item.Category)
+		if policy != domain.RepeatPenalize {
+			return 0
+		}
+		if past.Items[item.Id] {
+			return 2
+		}
+		return 1
+	}
+
+	ordered := append([]domain.CatalogItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank(ordered[i]) < rank(ordered[j])
+	})
+	return ordered
+}
+
+// checkHistory отклоняет предмет, повтор которого запрещен.
+func checkHistory(decision *domain.SelectionDecision, item domain.GiftItem, p Params) bool {
+	policy, reason := p.History.Repeats(p.Past, item.ID, item.Category)
+	if policy != domain.RepeatForbid {
+		return true
+	}
+	decision.Outcome = domain.OutcomeRejectedHistory
+	decision.Reason = reason
+	return false
+}
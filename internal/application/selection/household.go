@@ -0,0 +1,102 @@
+package selection
+
+import (
+	"fmt"
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// PreferUnused переставляет предметы, уже выданные братьям и сестрам, в конец
+// каталога, чтобы дети одной семьи по возможности получили разные предметы.
+func PreferUnused(items []domain.CatalogItem, given map[int]bool) []domain.CatalogItem {
+	if len(given) == 0 {
+		return items
+	}
+
+	ordered := append([]domain.CatalogItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !given[ordered[i].Id] && given[ordered[j].Id]
+	})
+	return ordered
+}
+
+// WithoutShared исключает из каталога категории, выдаваемые один раз на семью.
+func WithoutShared(items []domain.CatalogItem, rules *domain.HouseholdRules) []domain.CatalogItem {
+	if rules == nil || len(rules.SharedCategories) == 0 {
+		return items
+	}
+
+	result := make([]domain.CatalogItem, 0, len(items))
+	for _, item := range items {
+		if !rules.Shared(item.Category) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// SelectShared подбирает общий предмет категории для семьи.
+// Предмет должен подходить каждому ребенку по возрасту и требованиям
+// и помещаться в подарок carrier, куда он кладется: в остаток бюджета
+// с учетом доплаты за доставку, а также в ограничения по весу и количеству.
+func SelectShared(children []domain.Child, catalog []domain.CatalogItem, category string, carrier Result, p Params) (domain.GiftSelection, bool) {
+	if p.Coefficient <= 0 {
+		p.Coefficient = 1.0
+	}
+
+	if p.MaxCount > 0 && len(carrier.Items) >= p.MaxCount {
+		return domain.GiftSelection{}, false
+	}
+
+	for _, catalogItem := range catalog {
+		if catalogItem.Category != category {
+			continue
+		}
+
+		item := catalogItem.ToGiftItem()
+		if !suitsAll(children, item, p.Severity) {
+			continue
+		}
+
+		price := item.GetPriceWithCoefficient(p.Coefficient)
+		if carrier.Cost+price+p.delivery(carrier.Weight+item.Weight) > p.MaxBudget {
+			continue
+		}
+		if p.MaxWeight > 0 && carrier.Weight+item.Weight > p.MaxWeight {
+			continue
+		}
+		if !p.Stock.Available(item.ID) {
+			continue
+		}
+		p.Stock.Take(item.ID)
+
+		compliance := make(map[string]bool)
+		for _, child := range children {
+			for key, ok := range item.GetComplianceSummary(child.SpecialRequirements) {
+				compliance[key] = ok
+			}
+		}
+
+		return domain.GiftSelection{
+			ItemID:          item.ID,
+			ItemName:        item.Name,
+			Category:        item.Category,
+			Price:           price,
+			Weight:          item.Weight,
+			SelectionReason: fmt.Sprintf("Общий предмет семьи из %d детей", len(children)),
+			ComplianceCheck: compliance,
+		}, true
+	}
+
+	return domain.GiftSelection{}, false
+}
+
+func suitsAll(children []domain.Child, item domain.GiftItem, severity domain.RequirementSeverity) bool {
+	for _, child := range children {
+		if _, ok := checkEligibility(child, item, severity); !ok {
+			return false
+		}
+	}
+	return true
+}
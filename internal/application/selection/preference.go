@@ -0,0 +1,39 @@
+package selection
+
+import (
+	"fmt"
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// PreferMatching переставляет вперед предметы, выполняющие больше мягких
+// требований ребенка. При равном числе сохраняется исходный порядок.
+func PreferMatching(items []domain.CatalogItem, child domain.Child, severity domain.RequirementSeverity) []domain.CatalogItem {
+	_, soft := severity.Split(child.SpecialRequirements)
+	if soft == nil {
+		return items
+	}
+
+	scores := make(map[int]int, len(items))
+	for _, item := range items {
+		gift := item.ToGiftItem()
+		scores[item.Id], _ = gift.PreferenceScore(soft)
+	}
+
+	ordered := append([]domain.CatalogItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return scores[ordered[i].Id] > scores[ordered[j].Id]
+	})
+	return ordered
+}
+
+// preferenceNote описывает выполнение мягких требований для трассировки.
+func preferenceNote(child domain.Child, item domain.GiftItem, severity domain.RequirementSeverity) string {
+	_, soft := severity.Split(child.SpecialRequirements)
+	met, total := item.PreferenceScore(soft)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", предпочтений выполнено %d из %d", met, total)
+}
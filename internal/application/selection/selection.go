@@ -0,0 +1,281 @@
+package selection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"giftcalc/internal/domain"
+)
+
+// Params содержит ограничения подбора подарка для одного ребенка.
+type Params struct {
+	// MaxCount - максимальное количество позиций в подарке.
+	MaxCount int
+
+	// MaxBudget - максимальная стоимость подарка с учетом коэффициента региона.
+	MaxBudget domain.Money
+
+	// MaxCost - максимальная стоимость предметов без доставки, 0 - без ограничения.
+	MaxCost domain.Money
+
+	// MaxWeight - максимальный вес подарка, 0 - без ограничения.
+	MaxWeight float64
+
+	// Coefficient - региональный коэффициент цены, 0 трактуется как 1.
+	Coefficient float64
+
+	// Explain включает запись решения по каждому кандидату.
+	Explain bool
+
+	// Stock - общий складской запас; nil означает неограниченный запас.
+	Stock *Stock
+
+	// Composition - правила состава подарка; nil означает отсутствие правил.
+	Composition *domain.CompositionRules
+
+	// History и Past - правила учета прошлых подарков и подарки ребенка прошлых лет.
+	History *domain.HistoryRules
+	Past    domain.PastGifts
+
+	// Severity - строгость категорий требований; мягкие требования не исключают
+	// предмет, а только поднимают подходящие предметы выше (см. PreferMatching).
+	Severity domain.RequirementSeverity
+
+	// Delivery - тариф доставки, который входит в бюджет подарка;
+	// nil означает, что бюджет ограничивает только стоимость предметов.
+	Delivery *domain.DeliveryTariff
+}
+
+// Result содержит результат подбора подарка.
+type Result struct {
+	Items  []domain.GiftSelection
+	Cost   domain.Money
+	Weight float64
+
+	// Trace заполняется только при Params.Explain.
+	Trace []domain.SelectionDecision
+
+	// Shortages - предметы, которые подошли бы ребенку, но закончились на складе.
+	Shortages []Shortage
+}
+
+// Shortage - предмет, закончившийся на складе, и его замена: предмет той же
+// категории, выбранный после нехватки. SubstituteID = 0 - замены нет.
+type Shortage struct {
+	ItemID       int
+	Category     string
+	SubstituteID int
+}
+
+// short отмечает нехватку предмета; повторная нехватка того же предмета не учитывается.
+func (res *Result) short(item domain.GiftItem) {
+	for _, s := range res.Shortages {
+		if s.ItemID == item.ID {
+			return
+		}
+	}
+	res.Shortages = append(res.Shortages, Shortage{ItemID: item.ID, Category: item.Category})
+}
+
+// Substitutions разделяет нехватки на замененные и оставшиеся без замены.
+func (res *Result) Substitutions() (missing, substitutes, unreplaced []int) {
+	for _, s := range res.Shortages {
+		if s.SubstituteID == 0 {
+			unreplaced = append(unreplaced, s.ItemID)
+			continue
+		}
+		missing = append(missing, s.ItemID)
+		substitutes = append(substitutes, s.SubstituteID)
+	}
+	return missing, substitutes, unreplaced
+}
+
+// Select подбирает подарок для ребенка из каталога.
+// Предметы перебираются в переданном порядке, порядок задает стратегия.
+// Если заданы правила состава, сначала закрываются обязательные категории,
+// затем подарок дополняется остальными предметами.
+func Select(child domain.Child, catalog []domain.CatalogItem, p Params) Result {
+	if p.Coefficient <= 0 {
+		p.Coefficient = 1.0
+	}
+
+	res := Result{Items: make([]domain.GiftSelection, 0, p.MaxCount)}
+	trace := func(d domain.SelectionDecision) {
+		if p.Explain {
+			res.Trace = append(res.Trace, d)
+		}
+	}
+
+	used := make([]bool, len(catalog))
+
+	// 1. Обязательные категории из правил состава
+	minimums := p.Composition.MinimumsFor(&child)
+	if len(minimums) > 0 {
+		for i, catalogItem := range catalog {
+			if countCategory(res.Items, catalogItem.Category) >= minimums[catalogItem.Category] {
+				continue
+			}
+			if decision, ok := consider(&res, child, catalogItem, p); ok {
+				used[i] = true
+				trace(decision)
+			}
+		}
+	}
+
+	// 2. Остальные предметы
+	for i, catalogItem := range catalog {
+		if used[i] {
+			continue
+		}
+		decision, _ := consider(&res, child, catalogItem, p)
+		trace(decision)
+	}
+
+	return res
+}
+
+// consider проверяет предмет-кандидат и добавляет его в подарок, если он подходит.
+func consider(res *Result, child domain.Child, catalogItem domain.CatalogItem, p Params) (domain.SelectionDecision, bool) {
+	item := catalogItem.ToGiftItem()
+	decision, ok := checkEligibility(child, item, p.Severity)
+	if !ok {
+		return decision, false
+	}
+
+	// Проверить повтор подарков прошлых лет
+	if !checkHistory(&decision, item, p) {
+		return decision, false
+	}
+
+	// Проверить количество позиций
+	if len(res.Items) >= p.MaxCount {
+		decision.Outcome = domain.OutcomeSkippedCount
+		decision.Reason = fmt.Sprintf("Достигнуто максимальное количество позиций: %d", p.MaxCount)
+		return decision, false
+	}
+
+	// Проверить правила состава подарка
+	if ok, reason := p.Composition.Allows(res.Items, item.Category); !ok {
+		decision.Outcome = domain.OutcomeRejectedComposition
+		decision.Reason = reason
+		return decision, false
+	}
+
+	// Проверить бюджетные ограничения
+	itemPrice := item.GetPriceWithCoefficient(p.Coefficient)
+	if res.Cost+itemPrice+p.delivery(res.Weight+item.Weight) > p.MaxBudget {
+		decision.Outcome = domain.OutcomeSkippedBudget
+		decision.Reason = fmt.Sprintf("Цена %s превышает остаток бюджета %s", itemPrice, p.MaxBudget-res.Cost-p.delivery(res.Weight))
+		return decision, false
+	}
+	if p.MaxCost > 0 && res.Cost+itemPrice > p.MaxCost {
+		decision.Outcome = domain.OutcomeSkippedBudget
+		decision.Reason = fmt.Sprintf("Цена %s превышает остаток лимита стоимости предметов %s", itemPrice, p.MaxCost-res.Cost)
+		return decision, false
+	}
+
+	// Проверить ограничение по весу
+	if p.MaxWeight > 0 && res.Weight+item.Weight > p.MaxWeight {
+		decision.Outcome = domain.OutcomeSkippedWeight
+		decision.Reason = fmt.Sprintf("Вес %.2f превышает остаток %.2f", item.Weight, p.MaxWeight-res.Weight)
+		return decision, false
+	}
+
+	// Проверить остаток на складе
+	if !p.Stock.Available(item.ID) {
+		decision.Outcome = domain.OutcomeSkippedStock
+		decision.Reason = "Предмет закончился на складе"
+		res.short(item)
+		return decision, false
+	}
+	p.Stock.Take(item.ID)
+
+	res.add(child, item, itemPrice, "Подходит по возрасту, требованиям и бюджету")
+
+	decision.Outcome = domain.OutcomeAccepted
+	decision.Reason = fmt.Sprintf("Цена %s, вес %.2f", itemPrice, item.Weight) + preferenceNote(child, item, p.Severity)
+	return decision, true
+}
+
+// checkEligibility проверяет возраст и жесткие специальные требования ребенка.
+func checkEligibility(child domain.Child, item domain.GiftItem, severity domain.RequirementSeverity) (domain.SelectionDecision, bool) {
+	decision := domain.SelectionDecision{ItemID: item.ID, ItemName: item.Name}
+
+	// Проверить возрастные ограничения
+	if item.MinAge > child.Age {
+		decision.Outcome = domain.OutcomeRejectedAge
+		decision.Reason = fmt.Sprintf("Требуется %d лет, ребенку %d", item.MinAge, child.Age)
+		return decision, false
+	}
+
+	// Проверить жесткие специальные требования
+	hard, _ := severity.Split(child.SpecialRequirements)
+	if violations := item.ValidateRequirementsCompliance(hard); len(violations) > 0 {
+		decision.Outcome = domain.OutcomeRejectedRequirement
+		decision.Reason = strings.Join(violations, "; ")
+		decision.Requirements = failedRequirements(item, hard)
+		return decision, false
+	}
+
+	return decision, true
+}
+
+// add добавляет предмет в подарок.
+func (res *Result) add(child domain.Child, item domain.GiftItem, price domain.Money, reason string) {
+	res.Cost += price
+	res.Weight += item.Weight
+	for i := range res.Shortages {
+		if s := &res.Shortages[i]; s.SubstituteID == 0 && s.Category == item.Category {
+			s.SubstituteID = item.ID
+			break
+		}
+	}
+	res.Items = append(res.Items, domain.GiftSelection{
+		ItemID:          item.ID,
+		ItemName:        item.Name,
+		Category:        item.Category,
+		Price:           price,
+		Weight:          item.Weight,
+		SelectionReason: reason,
+		ComplianceCheck: item.GetComplianceSummary(child.SpecialRequirements),
+	})
+}
+
+// AddShared кладет в подарок общий предмет семьи, подобранный SelectShared.
+func (res *Result) AddShared(shared domain.GiftSelection) {
+	res.Cost += shared.Price
+	res.Weight += shared.Weight
+	res.Items = append(res.Items, shared)
+}
+
+// delivery возвращает стоимость доставки корзины указанного веса,
+// если доставка входит в бюджет подарка.
+func (p Params) delivery(weight float64) domain.Money {
+	if p.Delivery == nil {
+		return 0
+	}
+	return p.Delivery.Cost(weight)
+}
+
+func countCategory(selected []domain.GiftSelection, category string) int {
+	n := 0
+	for _, s := range selected {
+		if s.Category == category {
+			n++
+		}
+	}
+	return n
+}
+
+// failedRequirements возвращает ключи требований, которым предмет не соответствует.
+func failedRequirements(item domain.GiftItem, reqs *domain.SpecialRequirements) []string {
+	var failed []string
+	for key, ok := range item.GetComplianceSummary(reqs) {
+		if !ok {
+			failed = append(failed, key)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
@@ -0,0 +1,78 @@
+package selection
+
+import (
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// Stock - общий складской запас, из которого подбираются подарки.
+// Предметы без указанного остатка считаются неограниченными.
+type Stock struct {
+	initial   map[int]int
+	remaining map[int]int
+	names     map[int]string
+}
+
+// NewStock создает запас по остаткам из каталога.
+func NewStock(items []domain.CatalogItem) *Stock {
+	s := &Stock{
+		initial:   make(map[int]int),
+		remaining: make(map[int]int),
+		names:     make(map[int]string),
+	}
+	for _, item := range items {
+		if item.Stock == nil {
+			continue
+		}
+		s.initial[item.Id] = *item.Stock
+		s.remaining[item.Id] = *item.Stock
+		s.names[item.Id] = item.Name
+	}
+	return s
+}
+
+// Available сообщает, остался ли предмет на складе.
+func (s *Stock) Available(itemID int) bool {
+	if s == nil {
+		return true
+	}
+	left, limited := s.remaining[itemID]
+	return !limited || left > 0
+}
+
+// Take списывает одну единицу предмета.
+func (s *Stock) Take(itemID int) {
+	if s == nil {
+		return
+	}
+	if _, limited := s.remaining[itemID]; limited {
+		s.remaining[itemID]--
+	}
+}
+
+// Consumption возвращает расход по каждому ограниченному предмету.
+func (s *Stock) Consumption() []domain.StockConsumption {
+	result := make([]domain.StockConsumption, 0, len(s.initial))
+	for id, initial := range s.initial {
+		result = append(result, domain.StockConsumption{
+			ItemID:    id,
+			ItemName:  s.names[id],
+			Initial:   initial,
+			Consumed:  initial - s.remaining[id],
+			Remaining: s.remaining[id],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ItemID < result[j].ItemID })
+	return result
+}
+
+// Release возвращает на склад одну единицу предмета, например при пересборке подарка.
+func (s *Stock) Release(itemID int) {
+	if s == nil {
+		return
+	}
+	if _, limited := s.remaining[itemID]; limited {
+		s.remaining[itemID]++
+	}
+}
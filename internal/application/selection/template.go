@@ -0,0 +1,140 @@
+package selection
+
+import (
+	"fmt"
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// FillTemplate заполняет слоты шаблона подарка для ребенка.
+// В слот ставится предмет по умолчанию, если он подходит ребенку; иначе
+// выбирается подходящий предмет той же категории с ценой, ближайшей к
+// предмету по умолчанию (или к верхней границе слота).
+// Вторым значением возвращаются примечания о незаполненных обязательных слотах.
+func FillTemplate(child domain.Child, tmpl *domain.GiftTemplate, catalog []domain.CatalogItem, p Params) (Result, []string) {
+	if p.Coefficient <= 0 {
+		p.Coefficient = 1.0
+	}
+
+	res := Result{Items: make([]domain.GiftSelection, 0, len(tmpl.Slots))}
+	used := make(map[int]bool)
+	var notes []string
+
+	byID := make(map[int]domain.CatalogItem, len(catalog))
+	for _, item := range catalog {
+		byID[item.Id] = item
+	}
+
+	for _, slot := range tmpl.Slots {
+		reference := slot.MaxPrice
+		filled := false
+		if def, ok := byID[slot.DefaultItemID]; ok && !used[def.Id] {
+			if slot.Fits(def) {
+				reference = def.Price
+			}
+			decision, ok := fillSlot(&res, child, slot, def, p, "Предмет слота по умолчанию")
+			res.trace(p, decision)
+			if ok {
+				used[def.Id] = true
+				continue
+			}
+		}
+
+		for _, item := range PreferFresh(PreferMatching(slotCandidates(catalog, slot, reference), child, p.Severity), p.History, p.Past) {
+			if used[item.Id] || item.Id == slot.DefaultItemID {
+				continue
+			}
+			decision, ok := fillSlot(&res, child, slot, item, p, "Замена предмета слота по умолчанию")
+			res.trace(p, decision)
+			if ok {
+				used[item.Id] = true
+				filled = true
+				break
+			}
+		}
+
+		if !filled && !slot.Optional {
+			notes = append(notes, fmt.Sprintf("Слот %q (%s) шаблона %s не заполнен", slot.Name, slot.Category, tmpl.Name))
+		}
+	}
+
+	return res, notes
+}
+
+// fillSlot проверяет предмет для слота и добавляет его в подарок.
+func fillSlot(res *Result, child domain.Child, slot domain.TemplateSlot, catalogItem domain.CatalogItem, p Params, reason string) (domain.SelectionDecision, bool) {
+	item := catalogItem.ToGiftItem()
+	if !slot.Fits(catalogItem) {
+		return domain.SelectionDecision{
+			ItemID:   item.ID,
+			ItemName: item.Name,
+			Outcome:  domain.OutcomeRejectedComposition,
+			Reason:   fmt.Sprintf("Слот %q: предмет не подходит по категории %s или цене", slot.Name, slot.Category),
+		}, false
+	}
+
+	decision, ok := checkEligibility(child, item, p.Severity)
+	decision.Reason = fmt.Sprintf("Слот %q: %s", slot.Name, decision.Reason)
+	if !ok {
+		return decision, false
+	}
+
+	if !checkHistory(&decision, item, p) {
+		decision.Reason = fmt.Sprintf("Слот %q: %s", slot.Name, decision.Reason)
+		return decision, false
+	}
+
+	itemPrice := item.GetPriceWithCoefficient(p.Coefficient)
+	if res.Cost+itemPrice+p.delivery(res.Weight+item.Weight) > p.MaxBudget {
+		decision.Outcome = domain.OutcomeSkippedBudget
+		decision.Reason = fmt.Sprintf("Слот %q: цена %s превышает остаток бюджета %s", slot.Name, itemPrice, p.MaxBudget-res.Cost-p.delivery(res.Weight))
+		return decision, false
+	}
+
+	if p.MaxWeight > 0 && res.Weight+item.Weight > p.MaxWeight {
+		decision.Outcome = domain.OutcomeSkippedWeight
+		decision.Reason = fmt.Sprintf("Слот %q: вес %.2f превышает остаток %.2f", slot.Name, item.Weight, p.MaxWeight-res.Weight)
+		return decision, false
+	}
+
+	if !p.Stock.Available(item.ID) {
+		decision.Outcome = domain.OutcomeSkippedStock
+		decision.Reason = fmt.Sprintf("Слот %q: предмет закончился на складе", slot.Name)
+		res.short(item)
+		return decision, false
+	}
+	p.Stock.Take(item.ID)
+
+	res.add(child, item, itemPrice, fmt.Sprintf("%s: %s", reason, slot.Name))
+
+	decision.Outcome = domain.OutcomeAccepted
+	decision.Reason = fmt.Sprintf("Слот %q: цена %s, вес %.2f", slot.Name, itemPrice, item.Weight)
+	return decision, true
+}
+
+// slotCandidates возвращает предметы категории слота, подходящие по цене,
+// в порядке близости цены к опорной.
+func slotCandidates(catalog []domain.CatalogItem, slot domain.TemplateSlot, reference domain.Money) []domain.CatalogItem {
+	var candidates []domain.CatalogItem
+	for _, item := range catalog {
+		if item.Category == slot.Category && slot.Allows(item.Price) {
+			candidates = append(candidates, item)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if reference <= 0 {
+			return candidates[i].Price > candidates[j].Price
+		}
+		return (candidates[i].Price - reference).Abs() < (candidates[j].Price - reference).Abs()
+	})
+	return candidates
+}
+
+// trace записывает решение, если включена трассировка.
+func (res *Result) trace(p Params, d domain.SelectionDecision) {
+	if p.Explain {
+		res.Trace = append(res.Trace, d)
+	}
+}
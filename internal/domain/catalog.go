@@ -3,13 +3,25 @@ package domain
 import "time"
 
 type CatalogItem struct {
-	Id       int      `json:"id"`
-	Name     string   `json:"name"`
-	Category string   `json:"category"`
-	Price    float64  `json:"price"`
-	Weight   float64  `json:"weight"`
-	MinAge   int      `json:"min_age"`
+	Id       int      `json:"id" jsonschema:"required,minimum=1"`
+	Name     string   `json:"name" jsonschema:"required,minLength=1"`
+	Category string   `json:"category" jsonschema:"required,minLength=1"`
+	Price    Money    `json:"price" jsonschema:"required,minimum=0"`
+	Weight   float64  `json:"weight" jsonschema:"minimum=0"`
+	MinAge   int      `json:"min_age" jsonschema:"minimum=0"`
 	Metadata Metadata `json:"metadata"`
+
+	// Volume - объем предмета в упаковке, л; 0 - не указан.
+	Volume float64 `json:"volume,omitempty" jsonschema:"minimum=0"`
+
+	// Dimensions - габариты предмета в упаковке; nil - не указаны.
+	Dimensions *Dimensions `json:"dimensions,omitempty"`
+
+	// Stock - остаток на складе мастерских, nil означает неограниченный запас.
+	Stock *int `json:"stock,omitempty" jsonschema:"minimum=0"`
+
+	// Currency - валюта цены (ISO 4217); пусто - базовая валюта кампании.
+	Currency string `json:"currency,omitempty"`
 }
 
 type Metadata struct {
@@ -57,9 +69,9 @@ type CatalogData struct {
 		TotalItems      int `json:"total_items"`
 		TotalCategories int `json:"total_categories"`
 		PriceRange      struct {
-			Min float64 `json:"min"`
-			Max float64 `json:"max"`
-			Avg float64 `json:"avg"`
+			Min Money `json:"min"`
+			Max Money `json:"max"`
+			Avg Money `json:"avg"`
 		} `json:"price_range"`
 		AgeRange struct {
 			Min int `json:"min"`
@@ -76,5 +88,64 @@ type CatalogData struct {
 		MinAge      int     `json:"min_age"`
 		MaxAge      int     `json:"max_age"`
 	} `json:"categories"`
-	Items []CatalogItem `json:"items"`
+	Items []CatalogItem `json:"items" jsonschema:"required"`
+}
+
+// ToGiftItem преобразует позицию каталога в предмет подарка
+// для проверки соответствия специальным требованиям.
+func (c CatalogItem) ToGiftItem() GiftItem {
+	m := c.Metadata
+	return GiftItem{
+		ID:       c.Id,
+		Name:     c.Name,
+		Category: c.Category,
+		Price:    c.Price,
+		Weight:   c.Weight,
+		MinAge:   c.MinAge,
+		Metadata: GiftMetadata{
+			ContainsDairy:      m.ContainsDairy,
+			ContainsNuts:       m.ContainsNuts,
+			ContainsGluten:     m.ContainsGluten,
+			ContainsSugar:      m.ContainsSugar,
+			SugarFree:          m.SugarFree,
+			Vegetarian:         m.Vegetarian,
+			Vegan:              m.Vegan,
+			HalalCertified:     m.HalalCertified,
+			KosherCertified:    m.KosherCertified,
+			HasSmallParts:      m.HasSmallParts,
+			SmallPartsSize:     m.SmallPartsSize,
+			Hypoallergenic:     m.Hypoallergenic,
+			NonToxic:           m.NonToxic,
+			Washable:           m.Washable,
+			FlameRetardant:     m.FlameRetardant,
+			BPAFree:            m.BpaFree,
+			HasFlashingLights:  m.HasFlashingLights,
+			HasFuzzyMaterial:   m.HasFuzzyMaterial,
+			IsDusty:            m.IsDusty,
+			CalmingEffect:      m.CalmingEffect,
+			Tactile:            m.Tactile,
+			Predictable:        m.Predictable,
+			WirelessCompatible: m.WirelessCompatible,
+			AccessibleSize:     m.AccessibleSize,
+			EcoFriendly:        m.EcoFriendly,
+			Educational:        m.Educational,
+			GenderNeutral:      m.GenderNeutral,
+			Bilingual:          m.Bilingual,
+			Durable:            m.Durable,
+			Repairable:         m.Repairable,
+			CharitySupported:   m.CharitySupported,
+			Materials:          m.Materials,
+			Certifications:     m.Certifications,
+			Warnings:           m.Warnings,
+		},
+	}
+}
+
+// PackedVolume возвращает объем предмета, л: указанный явно или
+// рассчитанный по габаритам.
+func (c CatalogItem) PackedVolume() float64 {
+	if c.Volume == 0 && c.Dimensions != nil {
+		return c.Dimensions.Volume()
+	}
+	return c.Volume
 }
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Attestations - свойства, которые может подтверждать сертификат.
+// В правилах соответствия им соответствуют имена certified_<свойство>
+// (есть действующий сертификат) и revoked_<свойство> (есть только просроченный).
+var Attestations = []string{
+	"halal",
+	"kosher",
+	"non_toxic",
+	"hypoallergenic",
+	"bpa_free",
+	"flame_retardant",
+	"eco_friendly",
+	"safety",
+	"en71",
+}
+
+// Certificate - сертификат из реестра.
+type Certificate struct {
+	ID string `json:"id" jsonschema:"required,minLength=1"`
+
+	// Names - написания сертификата в каталоге, сравниваются без учета регистра.
+	Names []string `json:"names" jsonschema:"required"`
+
+	// Attests - подтверждаемые свойства (см. Attestations).
+	Attests []string `json:"attests" jsonschema:"required"`
+
+	// Issuer - орган, выдавший сертификат.
+	Issuer string `json:"issuer,omitempty"`
+
+	// Expires - последний день действия в формате ГГГГ-ММ-ДД; пусто - бессрочный.
+	Expires string `json:"expires,omitempty"`
+}
+
+// ExpiredAt сообщает, истек ли срок действия сертификата на указанный момент.
+func (c Certificate) ExpiredAt(t time.Time) bool {
+	return c.Expires != "" && t.Format(time.DateOnly) > c.Expires
+}
+
+// CertificationRegistry - реестр известных сертификатов.
+type CertificationRegistry struct {
+	Version      string        `json:"version" jsonschema:"required,minLength=1"`
+	Certificates []Certificate `json:"certificates" jsonschema:"required"`
+}
+
+// Validate проверяет уникальность сертификатов, свойства и даты.
+func (r CertificationRegistry) Validate() error {
+	ids := make(map[string]bool, len(r.Certificates))
+	names := make(map[string]string)
+	for _, c := range r.Certificates {
+		if ids[c.ID] {
+			return fmt.Errorf("сертификат %s указан повторно", c.ID)
+		}
+		ids[c.ID] = true
+
+		if len(c.Names) == 0 {
+			return fmt.Errorf("сертификат %s: не указаны названия", c.ID)
+		}
+		for _, name := range c.Names {
+			key := certificateKey(name)
+			if key == "" {
+				return fmt.Errorf("сертификат %s: пустое название", c.ID)
+			}
+			if other, ok := names[key]; ok {
+				return fmt.Errorf("название %q относится к сертификатам %s и %s", name, other, c.ID)
+			}
+			names[key] = c.ID
+		}
+
+		for _, a := range c.Attests {
+			if !slices.Contains(Attestations, a) {
+				return fmt.Errorf("сертификат %s: неизвестное свойство %s (допустимо: %s)",
+					c.ID, a, strings.Join(Attestations, ", "))
+			}
+		}
+
+		if c.Expires != "" {
+			if _, err := time.Parse(time.DateOnly, c.Expires); err != nil {
+				return fmt.Errorf("сертификат %s: некорректная дата окончания %q", c.ID, c.Expires)
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve находит сертификат по написанию в каталоге.
+func (r CertificationRegistry) Resolve(name string) (Certificate, bool) {
+	key := certificateKey(name)
+	for _, c := range r.Certificates {
+		for _, n := range c.Names {
+			if certificateKey(n) == key {
+				return c, true
+			}
+		}
+	}
+	return Certificate{}, false
+}
+
+// certificateKey нормализует написание сертификата: регистр, "ё" и разделители не важны.
+func certificateKey(name string) string {
+	return strings.Join(Tokenize(name), " ")
+}
+
+// CertificationIssue - сертификат предмета, не прошедший проверку по реестру.
+type CertificationIssue struct {
+	ItemID        int    `json:"item_id"`
+	ItemName      string `json:"item_name"`
+	Certification string `json:"certification"`
+	// Expires заполняется для просроченных сертификатов; для неизвестных пусто.
+	Expires string `json:"expires,omitempty"`
+}
+
+// Unknown сообщает, что сертификата нет в реестре.
+func (i CertificationIssue) Unknown() bool {
+	return i.Expires == ""
+}
+
+// CheckCertifications возвращает неизвестные и просроченные на момент at сертификаты каталога.
+func (r CertificationRegistry) CheckCertifications(items []CatalogItem, at time.Time) []CertificationIssue {
+	var issues []CertificationIssue
+	for _, item := range items {
+		for _, name := range item.Metadata.Certifications {
+			c, ok := r.Resolve(name)
+			switch {
+			case !ok:
+				issues = append(issues, CertificationIssue{ItemID: item.Id, ItemName: item.Name, Certification: name})
+			case c.ExpiredAt(at):
+				issues = append(issues, CertificationIssue{ItemID: item.Id, ItemName: item.Name, Certification: name, Expires: c.Expires})
+			}
+		}
+	}
+	return issues
+}
+
+var (
+	certificationsMu sync.RWMutex
+	certifications   CertificationRegistry
+
+	// certificationTime - момент, на который проверяется срок действия сертификатов.
+	certificationTime = time.Now
+)
+
+// UseCertifications делает реестр текущим для проверок соответствия.
+func UseCertifications(r CertificationRegistry) {
+	certificationsMu.Lock()
+	defer certificationsMu.Unlock()
+	certifications = r
+}
+
+// CertificationsVersion возвращает версию текущего реестра; пусто, если реестр не подключен.
+func CertificationsVersion() string {
+	certificationsMu.RLock()
+	defer certificationsMu.RUnlock()
+	return certifications.Version
+}
+
+// ValidCertifications возвращает сертификаты предмета, которые учитываются
+// эвристиками по ключевым словам. Если реестр подключен, это только сертификаты,
+// найденные в реестре и действующие на текущий момент; без реестра - все.
+func (g *GiftItem) ValidCertifications() []string {
+	certificationsMu.RLock()
+	defer certificationsMu.RUnlock()
+
+	if certifications.Version == "" {
+		return g.Metadata.Certifications
+	}
+
+	now := certificationTime()
+	var valid []string
+	for _, name := range g.Metadata.Certifications {
+		if c, ok := certifications.Resolve(name); ok && !c.ExpiredAt(now) {
+			valid = append(valid, name)
+		}
+	}
+	return valid
+}
+
+// Attested сообщает, подтверждено ли свойство действующим сертификатом из реестра
+// (valid) и есть ли у предмета только просроченные сертификаты этого свойства (revoked).
+func (g *GiftItem) Attested(attestation string) (valid, revoked bool) {
+	certificationsMu.RLock()
+	defer certificationsMu.RUnlock()
+
+	now := certificationTime()
+	for _, name := range g.Metadata.Certifications {
+		c, ok := certifications.Resolve(name)
+		if !ok || !slices.Contains(c.Attests, attestation) {
+			continue
+		}
+		if c.ExpiredAt(now) {
+			revoked = true
+			continue
+		}
+		return true, false
+	}
+	return false, revoked
+}
@@ -174,7 +174,7 @@ type ChildrenData struct {
 			Other   []string `json:"other"`
 		} `json:"special_requirements_keys"`
 	} `json:"metadata"`
-	Children []Child `json:"children"`
+	Children []Child `json:"children" jsonschema:"required"`
 }
 
 // Child представляет информацию о ребенке.
@@ -182,19 +182,19 @@ type ChildrenData struct {
 type Child struct {
 	// ID - уникальный идентификатор ребенка.
 	// Должен быть уникальным в пределах системы.
-	ID int `json:"id"`
+	ID int `json:"id" jsonschema:"required,minimum=1"`
 
 	// Name - имя ребенка.
 	// Может содержать кириллицу и пробелы.
-	Name string `json:"name"`
+	Name string `json:"name" jsonschema:"required,minLength=1"`
 
 	// Age - возраст ребенка в полных годах.
 	// Используется для возрастной фильтрации подарков.
-	Age int `json:"age"`
+	Age int `json:"age" jsonschema:"required,minimum=0,maximum=18"`
 
 	// Region - регион проживания ребенка.
 	// Используется для расчета региональных коэффициентов.
-	Region string `json:"region"`
+	Region string `json:"region" jsonschema:"required,minLength=1"`
 
 	// Notes - дополнительные заметки о ребенке.
 	// Необязательное поле, может содержать произвольный текст.
@@ -204,6 +204,10 @@ type Child struct {
 	// Используется для групповой обработки и аналитики.
 	Tags []string `json:"tags,omitempty"`
 
+	// FamilyID - идентификатор семьи.
+	// Дети с одинаковым FamilyID получают подарки с учетом друг друга.
+	FamilyID string `json:"family_id,omitempty"`
+
 	// SpecialRequirements - специальные требования ребенка.
 	// Учитываются при подборе подарков для обеспечения безопасности и комфорта.
 	SpecialRequirements *SpecialRequirements `json:"special_requirements,omitempty"`
@@ -556,6 +560,11 @@ func (c *Child) AgeGroup() string {
 	}
 }
 
+// AllAgeGroups возвращает все возрастные группы в порядке возрастания.
+func AllAgeGroups() []string {
+	return []string{"toddlers", "preschoolers", "young_school", "teens", "older_teens"}
+}
+
 // GetDietaryRequirements возвращает диетические требования как строки.
 func (sr *SpecialRequirements) GetDietaryRequirements() []string {
 	if sr == nil {
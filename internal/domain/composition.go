@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// CategoryLimit задает минимальное и максимальное количество предметов категории в подарке.
+// Нулевое значение Max означает отсутствие ограничения сверху.
+type CategoryLimit struct {
+	Min int `json:"min,omitempty" jsonschema:"minimum=0"`
+	Max int `json:"max,omitempty" jsonschema:"minimum=0"`
+}
+
+// CompositionRules описывает структуру подарка: сколько предметов каждой
+// категории допустимо, какие категории обязательны для возрастной группы
+// и какие категории не сочетаются друг с другом.
+type CompositionRules struct {
+	// DefaultMaxPerCategory - ограничение для категорий без явного правила, 0 - без ограничения.
+	DefaultMaxPerCategory int `json:"default_max_per_category,omitempty"`
+
+	// Categories - ограничения по конкретным категориям.
+	Categories map[string]CategoryLimit `json:"categories,omitempty"`
+
+	// RequiredByAgeGroup - категории, обязательные для возрастной группы (см. Child.AgeGroup).
+	RequiredByAgeGroup map[string][]string `json:"required_by_age_group,omitempty"`
+
+	// MutuallyExclusive - группы категорий, из которых в подарке может быть только одна.
+	MutuallyExclusive [][]string `json:"mutually_exclusive,omitempty"`
+}
+
+// Validate проверяет корректность правил.
+func (r *CompositionRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.DefaultMaxPerCategory < 0 {
+		return fmt.Errorf("default_max_per_category не может быть отрицательным")
+	}
+
+	for category, limit := range r.Categories {
+		if limit.Min < 0 || limit.Max < 0 {
+			return fmt.Errorf("категория %s: ограничения не могут быть отрицательными", category)
+		}
+		if limit.Max > 0 && limit.Min > limit.Max {
+			return fmt.Errorf("категория %s: min (%d) больше max (%d)", category, limit.Min, limit.Max)
+		}
+	}
+
+	groups := AllAgeGroups()
+	for group := range r.RequiredByAgeGroup {
+		if !slices.Contains(groups, group) {
+			return fmt.Errorf("неизвестная возрастная группа: %s (допустимо: %v)", group, groups)
+		}
+	}
+
+	for _, set := range r.MutuallyExclusive {
+		if len(set) < 2 {
+			return fmt.Errorf("группа взаимоисключающих категорий должна содержать не менее двух категорий: %v", set)
+		}
+	}
+
+	return nil
+}
+
+// MaxFor возвращает максимальное количество предметов категории, 0 - без ограничения.
+func (r *CompositionRules) MaxFor(category string) int {
+	if r == nil {
+		return 0
+	}
+	if limit, ok := r.Categories[category]; ok && limit.Max > 0 {
+		return limit.Max
+	}
+	return r.DefaultMaxPerCategory
+}
+
+// MinimumsFor возвращает минимальное количество предметов по категориям для ребенка
+// с учетом обязательных категорий его возрастной группы.
+func (r *CompositionRules) MinimumsFor(child *Child) map[string]int {
+	result := make(map[string]int)
+	if r == nil {
+		return result
+	}
+
+	for category, limit := range r.Categories {
+		if limit.Min > 0 {
+			result[category] = limit.Min
+		}
+	}
+	for _, category := range r.RequiredByAgeGroup[child.AgeGroup()] {
+		result[category] = max(result[category], 1)
+	}
+	return result
+}
+
+// Allows проверяет, можно ли добавить предмет категории к уже выбранным.
+// Возвращает причину отказа если добавить нельзя.
+func (r *CompositionRules) Allows(selected []GiftSelection, category string) (bool, string) {
+	if r == nil {
+		return true, ""
+	}
+
+	counts := countByCategory(selected)
+
+	if limit := r.MaxFor(category); limit > 0 && counts[category] >= limit {
+		return false, fmt.Sprintf("В подарке уже %d предм. категории %s (максимум %d)", counts[category], category, limit)
+	}
+
+	for _, set := range r.MutuallyExclusive {
+		if !slices.Contains(set, category) {
+			continue
+		}
+		for _, other := range set {
+			if other != category && counts[other] > 0 {
+				return false, fmt.Sprintf("Категория %s не сочетается с уже выбранной категорией %s", category, other)
+			}
+		}
+	}
+
+	return true, ""
+}
+
+// Check возвращает список невыполненных правил для готового подарка.
+func (r *CompositionRules) Check(child *Child, selected []GiftSelection) []string {
+	if r == nil {
+		return nil
+	}
+
+	counts := countByCategory(selected)
+	minimums := r.MinimumsFor(child)
+
+	categories := make([]string, 0, len(minimums))
+	for category := range minimums {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var warnings []string
+	for _, category := range categories {
+		if counts[category] < minimums[category] {
+			warnings = append(warnings, fmt.Sprintf(
+				"Правило состава не выполнено: требуется не менее %d предм. категории %s, подобрано %d",
+				minimums[category], category, counts[category]))
+		}
+	}
+	return warnings
+}
+
+func countByCategory(selected []GiftSelection) map[string]int {
+	counts := make(map[string]int)
+	for _, s := range selected {
+		counts[s.Category]++
+	}
+	return counts
+}
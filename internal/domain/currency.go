@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// DefaultCurrency - базовая валюта кампании, если в настройках не указана другая.
+const DefaultCurrency = "RUB"
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidCurrency проверяет код валюты: три заглавные латинские буквы (ISO 4217).
+func ValidCurrency(code string) bool {
+	return currencyCode.MatchString(code)
+}
+
+// ExchangeRates - таблица курсов валют к базовой валюте кампании.
+type ExchangeRates struct {
+	// Base - базовая валюта (ISO 4217), в которой проверяются бюджеты.
+	Base string `json:"base" jsonschema:"required,minLength=3"`
+
+	// EffectiveDate - дата, на которую действуют курсы, в формате ГГГГ-ММ-ДД.
+	EffectiveDate string `json:"effective_date" jsonschema:"required,minLength=10"`
+
+	// Rates - стоимость одной единицы валюты в базовой валюте, например "EUR": 98.5.
+	Rates map[string]float64 `json:"rates" jsonschema:"required"`
+
+	// Source - источник курсов для сверки финансовым отделом.
+	Source string `json:"source,omitempty"`
+}
+
+// Validate проверяет коды валют, курсы и дату.
+func (r *ExchangeRates) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if !ValidCurrency(r.Base) {
+		return fmt.Errorf("некорректный код базовой валюты: %q", r.Base)
+	}
+	if _, err := time.Parse(time.DateOnly, r.EffectiveDate); err != nil {
+		return fmt.Errorf("некорректная дата курсов %q", r.EffectiveDate)
+	}
+	for code, rate := range r.Rates {
+		if !ValidCurrency(code) {
+			return fmt.Errorf("некорректный код валюты: %q", code)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("курс %s должен быть положительным", code)
+		}
+	}
+	return nil
+}
+
+// BaseCurrency возвращает базовую валюту таблицы; для nil - DefaultCurrency.
+func (r *ExchangeRates) BaseCurrency() string {
+	if r == nil {
+		return DefaultCurrency
+	}
+	return r.Base
+}
+
+// Convert переводит сумму в базовую валюту с округлением до копейки
+// (см. Money.MulCoefficient). Пустая валюта означает базовую.
+func (r *ExchangeRates) Convert(amount Money, currency string) (Money, error) {
+	if currency == "" || currency == r.BaseCurrency() {
+		return amount, nil
+	}
+	if r == nil {
+		return 0, fmt.Errorf("нет таблицы курсов для валюты %s", currency)
+	}
+
+	rate, ok := r.Rates[currency]
+	if !ok {
+		return 0, fmt.Errorf("нет курса %s к %s", currency, r.Base)
+	}
+	return amount.MulCoefficient(rate), nil
+}
+
+// ConvertCatalog возвращает копию каталога с ценами в базовой валюте.
+// Предмет с валютой, для которой нет курса, считается ошибкой каталога.
+func (r *ExchangeRates) ConvertCatalog(items []CatalogItem) ([]CatalogItem, error) {
+	result := make([]CatalogItem, len(items))
+	for i, item := range items {
+		price, err := r.Convert(item.Price, item.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("предмет %d (%s): %w", item.Id, item.Name, err)
+		}
+		item.Price = price
+		item.Currency = r.BaseCurrency()
+		result[i] = item
+	}
+	return result, nil
+}
+
+// ReportCurrency - валюта отчета и примененная таблица курсов.
+type ReportCurrency struct {
+	Base          string             `json:"base"`
+	EffectiveDate string             `json:"effective_date,omitempty"`
+	Rates         map[string]float64 `json:"rates,omitempty"`
+	Source        string             `json:"source,omitempty"`
+
+	// Used - валюты каталога, цены в которых были пересчитаны.
+	Used []string `json:"used,omitempty"`
+}
+
+// ReportCurrency описывает для отчета базовую валюту и курсы, по которым
+// пересчитаны цены предметов в валютах used.
+func (r *ExchangeRates) ReportCurrency(used []string) ReportCurrency {
+	if r == nil {
+		return ReportCurrency{Base: DefaultCurrency}
+	}
+
+	sort.Strings(used)
+	return ReportCurrency{
+		Base:          r.Base,
+		EffectiveDate: r.EffectiveDate,
+		Rates:         r.Rates,
+		Source:        r.Source,
+		Used:          used,
+	}
+}
+
+// CatalogCurrencies возвращает валюты каталога, отличные от базовой.
+func CatalogCurrencies(items []CatalogItem, base string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range items {
+		if item.Currency == "" || item.Currency == base || seen[item.Currency] {
+			continue
+		}
+		seen[item.Currency] = true
+		result = append(result, item.Currency)
+	}
+	sort.Strings(result)
+	return result
+}
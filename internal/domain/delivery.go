@@ -0,0 +1,46 @@
+package domain
+
+import "fmt"
+
+// DeliveryTariff - тариф доставки подарка в регион: фиксированный сбор
+// за подарок и ставка за килограмм веса корзины.
+type DeliveryTariff struct {
+	BaseFee Money `json:"base_fee" jsonschema:"minimum=0"`
+	PerKg   Money `json:"per_kg" jsonschema:"minimum=0"`
+}
+
+// Cost возвращает стоимость доставки корзины указанного веса (кг).
+// Ставка за килограмм умножается на вес с округлением до копейки.
+func (t DeliveryTariff) Cost(weight float64) Money {
+	return t.BaseFee + t.PerKg.MulCoefficient(weight)
+}
+
+// DeliveryRules - модель стоимости доставки. Тариф региона задается
+// в Region.Delivery, для регионов без тарифа действует Default.
+type DeliveryRules struct {
+	Default DeliveryTariff `json:"default"`
+}
+
+// Validate проверяет корректность тарифа по умолчанию.
+func (r *DeliveryRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+	return r.Default.Validate()
+}
+
+// Validate проверяет, что сбор и ставка неотрицательны.
+func (t DeliveryTariff) Validate() error {
+	if t.BaseFee < 0 || t.PerKg < 0 {
+		return fmt.Errorf("тариф доставки не может быть отрицательным")
+	}
+	return nil
+}
+
+// Tariff возвращает тариф региона или тариф по умолчанию.
+func (r *DeliveryRules) Tariff(region string, regions map[string]DeliveryTariff) DeliveryTariff {
+	if t, ok := regions[region]; ok {
+		return t
+	}
+	return r.Default
+}
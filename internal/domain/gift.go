@@ -10,7 +10,7 @@ type GiftItem struct {
 	ID       int     `json:"id"`
 	Name     string  `json:"name"`
 	Category string  `json:"category"`
-	Price    float64 `json:"price"`
+	Price    Money   `json:"price"`
 	Weight   float64 `json:"weight"`
 	MinAge   int     `json:"min_age"`
 
@@ -90,7 +90,7 @@ type GiftRepository interface {
 	FindAll() ([]GiftItem, error)
 
 	// FindCheaperAlternative ищет более дешевую альтернативу.
-	FindCheaperAlternative(item GiftItem, maxPrice float64) (*GiftItem, error)
+	FindCheaperAlternative(item GiftItem, maxPrice Money) (*GiftItem, error)
 
 	// FindByAgeRange возвращает подарки для указанного возрастного диапазона.
 	FindByAgeRange(minAge, maxAge int) ([]GiftItem, error)
@@ -108,258 +108,58 @@ type GiftCatalogJSON struct {
 	Items       []GiftItem     `json:"items"`
 }
 
-// CompliesWithDietary проверяет соответствие подарка диетическому требованию.
+// CompliesWithDietary проверяет соответствие подарка диетическому требованию
+// по текущим правилам соответствия (см. UseRules).
 // Возвращает true если подарок соответствует требованию.
 func (g *GiftItem) CompliesWithDietary(req DietaryRequirement) bool {
-	switch req {
-	case DietaryVegetarian:
-		// Вегетарианство: исключить продукты с мясом, рыбой
-		return !g.Metadata.ContainsMeat && !g.Metadata.ContainsFish || g.Metadata.Vegetarian
-
-	case DietaryVegan:
-		// Веганство: исключить все продукты животного происхождения
-		return (!g.Metadata.ContainsMeat && !g.Metadata.ContainsFish &&
-			!g.Metadata.ContainsDairy) || g.Metadata.Vegan
-
-	case DietaryNutsAllergy:
-		// Аллергия на орехи: исключить орехи и следы орехов
-		return !g.Metadata.ContainsNuts
-
-	case DietaryLactoseIntolerant:
-		// Непереносимость лактозы: исключить молочные продукты
-		return !g.Metadata.ContainsDairy
-
-	case DietaryGlutenFree:
-		// Без глютена: исключить пшеницу, ячмень, рожь
-		return !g.Metadata.ContainsGluten
-
-	case DietaryDiabetes:
-		// Диабет: ограничить сахар, использовать сахарозаменители
-		return !g.Metadata.ContainsSugar || g.Metadata.SugarFree
-
-	case DietaryHalal:
-		// Халяль: соответствие исламским нормам
-		return g.Metadata.HalalCertified ||
-			(!g.ContainsPork() && g.IsSlaughteredAccordingToHalal())
-
-	case DietaryKosher:
-		// Кошерность: соответствие иудейским нормам
-		return g.Metadata.KosherCertified || g.IsKosherByIngredients()
-
-	default:
-		return true // Неизвестное требование - пропускаем
-	}
+	return evaluateRule("dietary", string(req), g)
 }
 
 // CompliesWithSafety проверяет соответствие подарка требованию безопасности.
 // Возвращает true если подарок соответствует требованию.
 func (g *GiftItem) CompliesWithSafety(req SafetyRequirement) bool {
-	switch req {
-	case SafetyNoSmallParts:
-		// Без мелких деталей: детали > 3см для детей до 3 лет
-		return !g.Metadata.HasSmallParts || g.Metadata.SmallPartsSize >= 3.0
-
-	case SafetyHypoallergenic:
-		// Гипоаллергенность: без аллергенов в материалах
-		return g.Metadata.Hypoallergenic ||
-			!g.ContainsAllergenicMaterials()
-
-	case SafetyNonToxic:
-		// Нетоксичность: сертифицированные безопасные материалы
-		return g.Metadata.NonToxic ||
-			g.HasSafetyCertification()
-
-	case SafetyWashable:
-		// Моющийся: возможность стирки/мытья
-		return g.Metadata.Washable
-
-	case SafetyFlameRetardant:
-		// Огнестойкость: трудновоспламеняемые материалы
-		return g.Metadata.FlameRetardant
-
-	case SafetyBPAFree:
-		// Без BPA: без бисфенола-А в пластике
-		return g.Metadata.BPAFree ||
-			!g.ContainsBPA()
-
-	default:
-		return true // Неизвестное требование - пропускаем
-	}
+	return evaluateRule("safety", string(req), g)
 }
 
 // CompliesWithMedical проверяет соответствие подарка медицинскому требованию.
 // Возвращает true если подарок соответствует требованию.
 func (g *GiftItem) CompliesWithMedical(req MedicalRequirement) bool {
-	switch req {
-	case MedicalEpilepsy:
-		// Эпилепсия: избегать мигающих светодиодов
-		return !g.Metadata.HasFlashingLights
-
-	case MedicalAsthma:
-		// Астма: избегать пушистых/пылящих материалов
-		return !g.Metadata.HasFuzzyMaterial && !g.Metadata.IsDusty
-
-	case MedicalADHDFriendly:
-		// Для СДВГ: успокаивающие, фокусирующие игрушки
-		return g.Metadata.CalmingEffect ||
-			g.IsFocusEnhancing()
-
-	case MedicalAutismFriendly:
-		// Для аутизма: тактильные, предсказуемые игрушки
-		return (g.Metadata.Tactile || g.Metadata.Predictable) ||
-			g.IsAutismFriendlyByDesign()
-
-	case MedicalHearingAidCompatible:
-		// Слуховой аппарат: беспроводная совместимость
-		return g.Metadata.WirelessCompatible
-
-	case MedicalWheelchairAccessible:
-		// Инвалидная коляска: доступный размер/форма
-		return g.Metadata.AccessibleSize ||
-			g.IsWheelchairAccessibleByDesign()
-
-	default:
-		return true // Неизвестное требование - пропускаем
-	}
+	return evaluateRule("medical", string(req), g)
 }
 
 // CompliesWithOther проверяет соответствие подарка прочему требованию.
 // Возвращает true если подарок соответствует требованию.
 func (g *GiftItem) CompliesWithOther(req OtherRequirement) bool {
-	switch req {
-	case OtherEcoFriendly:
-		// Экологичность: перерабатываемые материалы
-		return g.Metadata.EcoFriendly ||
-			g.IsMadeFromRecycledMaterials()
-
-	case OtherEducational:
-		// Образовательный: развивающий характер
-		return g.Metadata.Educational ||
-			g.IsEducationalByCategory()
-
-	case OtherGenderNeutral:
-		// Гендерно-нейтральный: нейтральные цвета/темы
-		return g.Metadata.GenderNeutral ||
-			!g.IsGenderSpecific()
-
-	case OtherBilingual:
-		// Двуязычный: на двух языках
-		return g.Metadata.Bilingual
-
-	case OtherSustainable:
-		// Устойчивый: долговечный, ремонтопригодный
-		return (g.Metadata.Durable && g.Metadata.Repairable) ||
-			g.HasLongWarranty()
-
-	case OtherCharitySupported:
-		// Благотворительный: часть средств идет на благотворительность
-		return g.Metadata.CharitySupported
-
-	default:
-		return true // Неизвестное требование - пропускаем
-	}
+	return evaluateRule("other", string(req), g)
 }
 
 // ==================== Helper методы для проверки дополнительных условий ====================
 
 // ContainsPork проверяет содержит ли продукт свинину.
 func (g *GiftItem) ContainsPork() bool {
-	// Проверка по названию или материалам
-	porkKeywords := []string{"свинина", "pork", "bacon", "ham", "сало", "сальная", "свиной"}
-	nameLower := strings.ToLower(g.Name)
-
-	for _, keyword := range porkKeywords {
-		if strings.Contains(nameLower, keyword) {
-			return true
-		}
-	}
-
-	// Проверка материалов
-	for _, material := range g.Metadata.Materials {
-		materialLower := strings.ToLower(material)
-		for _, keyword := range porkKeywords {
-			if strings.Contains(materialLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка предупреждений
-	for _, warning := range g.Metadata.Warnings {
-		warningLower := strings.ToLower(warning)
-		for _, keyword := range porkKeywords {
-			if strings.Contains(warningLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	return false
+	// Проверка по названию, материалам и предупреждениям
+	return hasKeyword(KeywordsPork, g.Name) ||
+		hasKeyword(KeywordsPork, g.Metadata.Materials...) ||
+		hasKeyword(KeywordsPork, g.Metadata.Warnings...)
 }
 
 // IsSlaughteredAccordingToHalal проверяет соответствует ли продукт халяль.
 func (g *GiftItem) IsSlaughteredAccordingToHalal() bool {
 	// Упрощенная проверка - в реальной системе должна быть сложнее
-	halalKeywords := []string{"халяль", "halal", "ذَبِيحَة", "халал", "мусульманск", "islamic"}
-
-	// Проверка сертификаций
-	for _, cert := range g.Metadata.Certifications {
-		certLower := strings.ToLower(cert)
-		for _, keyword := range halalKeywords {
-			if strings.Contains(certLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в названии
-	nameLower := strings.ToLower(g.Name)
-	for _, keyword := range halalKeywords {
-		if strings.Contains(nameLower, keyword) {
-			return true
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsHalal, g.ValidCertifications()...) ||
+		hasKeyword(KeywordsHalal, g.Name)
 }
 
 // IsKosherByIngredients проверяет кошерность по ингредиентам.
 func (g *GiftItem) IsKosherByIngredients() bool {
-	kosherKeywords := []string{"кошер", "kosher", "כָּשֵׁר", "еврейск", "jewish", "иудейск"}
-
-	// Проверка сертификаций
-	for _, cert := range g.Metadata.Certifications {
-		certLower := strings.ToLower(cert)
-		for _, keyword := range kosherKeywords {
-			if strings.Contains(certLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в названии
-	nameLower := strings.ToLower(g.Name)
-	for _, keyword := range kosherKeywords {
-		if strings.Contains(nameLower, keyword) {
-			return true
-		}
+	// Проверка сертификаций и названия
+	if hasKeyword(KeywordsKosher, g.ValidCertifications()...) || hasKeyword(KeywordsKosher, g.Name) {
+		return true
 	}
 
-	// Проверка запрещенных ингредиентов (трефа)
-	nonKosherIngredients := []string{
-		"свинина", "pork", "моллюски", "shellfish", "ракообразные", "crustaceans",
-		"зайчатина", "hare", "верблюжатина", "camel", "хищные птицы", "birds of prey",
-		"осетрина", "sturgeon", "сом", "catfish", "угорь", "eel", "акула", "shark",
-	}
-
-	// Проверка материалов/ингредиентов на некошерность
-	for _, material := range g.Metadata.Materials {
-		materialLower := strings.ToLower(material)
-		for _, ingredient := range nonKosherIngredients {
-			if strings.Contains(materialLower, ingredient) {
-				return false
-			}
-		}
+	// Проверка материалов/ингредиентов на некошерность (трефа)
+	if hasKeyword(KeywordsNonKosher, g.Metadata.Materials...) {
+		return false
 	}
 
 	// Проверка смешивания мяса и молока
@@ -372,279 +172,66 @@ func (g *GiftItem) IsKosherByIngredients() bool {
 
 // ContainsAllergenicMaterials проверяет наличие аллергенных материалов.
 func (g *GiftItem) ContainsAllergenicMaterials() bool {
-	commonAllergens := []string{
-		"латекс", "latex", "шерсть", "wool", "пух", "down",
-		"пыльца", "pollen", "перо", "feather", "мех", "fur",
-		"шелк", "silk", "кашемир", "cashmere", "мохер", "mohair",
-		"плюш", "plush", "ворс", "nap", "бархат", "velvet",
-	}
-
-	for _, material := range g.Metadata.Materials {
-		materialLower := strings.ToLower(material)
-		for _, allergen := range commonAllergens {
-			if strings.Contains(materialLower, allergen) {
-				return true
-			}
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsAllergens, g.Metadata.Materials...)
 }
 
 // HasSafetyCertification проверяет наличие сертификатов безопасности.
 func (g *GiftItem) HasSafetyCertification() bool {
-	safetyCerts := []string{
-		"CE", "EN71", "ASTM", "ISO8124", "СТБ", "ГОСТ", "РСТ",
-		"безопасность", "safety", "сертификат", "certificate", "certification",
-		"стандарт", "standard", "соответствие", "compliance",
-	}
-
-	for _, cert := range g.Metadata.Certifications {
-		certUpper := strings.ToUpper(cert)
-		for _, safetyCert := range safetyCerts {
-			if strings.Contains(certUpper, safetyCert) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в предупреждениях
-	for _, warning := range g.Metadata.Warnings {
-		warningUpper := strings.ToUpper(warning)
-		for _, safetyCert := range safetyCerts {
-			if strings.Contains(warningUpper, safetyCert) {
-				return true
-			}
-		}
-	}
-
-	return false
+	// Проверка в сертификатах и предупреждениях
+	return hasKeyword(KeywordsSafetyCertification, g.ValidCertifications()...) ||
+		hasKeyword(KeywordsSafetyCertification, g.Metadata.Warnings...)
 }
 
 // ContainsBPA проверяет содержит ли продукт BPA.
 func (g *GiftItem) ContainsBPA() bool {
-	bpaKeywords := []string{"BPA", "бисфенол", "bisphenol", "BISPHENOL"}
-
-	for _, material := range g.Metadata.Materials {
-		materialUpper := strings.ToUpper(material)
-		for _, keyword := range bpaKeywords {
-			if strings.Contains(materialUpper, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в предупреждениях
-	for _, warning := range g.Metadata.Warnings {
-		warningUpper := strings.ToUpper(warning)
-		for _, keyword := range bpaKeywords {
-			if strings.Contains(warningUpper, keyword) {
-				return true
-			}
-		}
-	}
-
-	return false
+	// Проверка в материалах и предупреждениях
+	return hasKeyword(KeywordsBPA, g.Metadata.Materials...) ||
+		hasKeyword(KeywordsBPA, g.Metadata.Warnings...)
 }
 
 // IsFocusEnhancing проверяет способствует ли игрушка концентрации.
 func (g *GiftItem) IsFocusEnhancing() bool {
-	focusCategories := []string{
-		"конструктор", "constructor", "пазл", "puzzle", "головоломка",
-		"мозаика", "mosaic", "лабиринт", "labyrinth", "сортировщик",
-		"логический", "logic", "стратегия", "strategy", "шахматы", "chess",
-		"шашки", "checkers", "головолом", "brain teaser",
-	}
-
-	categoryLower := strings.ToLower(g.Category)
-	nameLower := strings.ToLower(g.Name)
-
-	allText := categoryLower + " " + nameLower
-	for _, focusCat := range focusCategories {
-		if strings.Contains(allText, focusCat) {
-			return true
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsFocus, g.Category, g.Name)
 }
 
 // IsAutismFriendlyByDesign проверяет дизайн на дружественность к аутизму.
 func (g *GiftItem) IsAutismFriendlyByDesign() bool {
-	autismFriendlyFeatures := []string{
-		"сенсорный", "sensory", "тактильный", "tactile", "успокаивающий",
-		"calming", "предсказуемый", "predictable", "структурированный",
-		"структурный", "структура", "устойчивый", "stable", "мягкий",
-		"soft", "тяжелый", "weighted", "антистресс", "anti-stress",
-		"релакс", "relax", "медитатив", "meditative",
-	}
-
-	nameLower := strings.ToLower(g.Name)
-	categoryLower := strings.ToLower(g.Category)
-
-	allText := nameLower + " " + categoryLower
-	for _, feature := range autismFriendlyFeatures {
-		if strings.Contains(allText, feature) {
-			return true
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsAutismFriendly, g.Name, g.Category)
 }
 
 // IsWheelchairAccessibleByDesign проверяет доступность для инвалидных колясок.
 func (g *GiftItem) IsWheelchairAccessibleByDesign() bool {
 	// Предметы, которые обычно доступны
-	accessibleCategories := []string{
-		"книга", "book", "диск", "disc", "программа", "software",
-		"аудио", "audio", "видео", "video", "электронный", "electronic",
-		"цифровой", "digital", "онлайн", "online", "приложение", "app",
-		"музыка", "music", "фильм", "movie", "плеер", "player",
-	}
-
-	categoryLower := strings.ToLower(g.Category)
-	nameLower := strings.ToLower(g.Name)
-
-	allText := categoryLower + " " + nameLower
-	for _, accessibleCat := range accessibleCategories {
-		if strings.Contains(allText, accessibleCat) {
-			return true
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsWheelchairAccessible, g.Category, g.Name)
 }
 
 // IsMadeFromRecycledMaterials проверяет сделано ли из переработанных материалов.
 func (g *GiftItem) IsMadeFromRecycledMaterials() bool {
-	recycledKeywords := []string{
-		"переработан", "recycled", "вторичн", "reclaimed", "восстановлен",
-		"upcycled", "эко", "eco", "биоразлагаем", "biodegradable",
-		"экологичн", "ecological", "природный", "natural", "органическ",
-		"organic", "компостируем", "compostable",
-	}
-
-	for _, material := range g.Metadata.Materials {
-		materialLower := strings.ToLower(material)
-		for _, keyword := range recycledKeywords {
-			if strings.Contains(materialLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в названии
-	nameLower := strings.ToLower(g.Name)
-	for _, keyword := range recycledKeywords {
-		if strings.Contains(nameLower, keyword) {
-			return true
-		}
-	}
-
-	return false
+	// Проверка в материалах и названии
+	return hasKeyword(KeywordsRecycled, g.Metadata.Materials...) ||
+		hasKeyword(KeywordsRecycled, g.Name)
 }
 
 // IsEducationalByCategory проверяет образовательную ценность по категории.
 func (g *GiftItem) IsEducationalByCategory() bool {
-	educationalCategories := []string{
-		"обучающий", "educational", "развивающий", "developmental",
-		"научный", "scientific", "познавательный", "informative",
-		"школьный", "school", "учебный", "study", "лаборатория", "lab",
-		"образовательн", "education", "развитие", "development",
-		"обучение", "learning", "просвещение", "enlightenment",
-		"учебник", "textbook", "атлас", "atlas", "глобус", "globe",
-		"химия", "chemistry", "физика", "physics", "биология", "biology",
-		"математика", "mathematics", "география", "geography",
-	}
-
-	categoryLower := strings.ToLower(g.Category)
-	nameLower := strings.ToLower(g.Name)
-
-	allText := categoryLower + " " + nameLower
-	for _, eduCat := range educationalCategories {
-		if strings.Contains(allText, eduCat) {
-			return true
-		}
-	}
-
-	return false
+	return hasKeyword(KeywordsEducational, g.Category, g.Name)
 }
 
 // IsGenderSpecific проверяет гендерную специфичность.
 func (g *GiftItem) IsGenderSpecific() bool {
-	genderSpecificKeywords := map[string][]string{
-		"male": {
-			"машинка", "car", "робот", "robot", "солдат", "soldier",
-			"пистолет", "gun", "трансформер", "transformer", "супергерой",
-			"superhero", "синий", "blue", "техника", "технический",
-			"конструктор", "constructor", "космос", "space", "динозавр", "dinosaur",
-			"гоночный", "racing", "полицейский", "police", "пожарный", "fire",
-			"армия", "army", "танк", "tank", "самолет", "airplane",
-		},
-		"female": {
-			"кукла", "doll", "принцесса", "princess", "пони", "pony",
-			"косметика", "cosmetics", "украшение", "jewelry", "розовый",
-			"pink", "фея", "fairy", "балет", "ballet", "мода", "fashion",
-			"русалка", "mermaid", "единорог", "unicorn", "сердечко", "heart",
-			"блеск", "glitter", "пастель", "pastel", "кухня", "kitchen",
-			"макияж", "makeup", "платье", "dress", "сумка", "bag",
-		},
-	}
-
-	nameLower := strings.ToLower(g.Name)
-	categoryLower := strings.ToLower(g.Category)
-
-	allText := nameLower + " " + categoryLower
-
 	// Если содержит ключевые слова обоих гендеров - считаем нейтральным
-	hasMale := false
-	hasFemale := false
-
-	for _, keyword := range genderSpecificKeywords["male"] {
-		if strings.Contains(allText, keyword) {
-			hasMale = true
-			break
-		}
-	}
-
-	for _, keyword := range genderSpecificKeywords["female"] {
-		if strings.Contains(allText, keyword) {
-			hasFemale = true
-			break
-		}
-	}
+	hasMale := hasKeyword(KeywordsGenderMale, g.Name, g.Category)
+	hasFemale := hasKeyword(KeywordsGenderFemale, g.Name, g.Category)
 
 	// Если есть только один тип ключевых слов - предмет гендерно-специфичный
-	return (hasMale && !hasFemale) || (hasFemale && !hasMale)
+	return hasMale != hasFemale
 }
 
 // HasLongWarranty проверяет наличие длительной гарантии.
 func (g *GiftItem) HasLongWarranty() bool {
-	warrantyKeywords := []string{
-		"гарантия", "warranty", "гарантийный", "guarantee",
-		"пожизненный", "lifetime", "долгий срок", "long term",
-		"продленная", "extended", "пятилетняя", "5 year",
-		"десятилетняя", "10 year", "пожизненная", "lifelong",
-	}
-
-	for _, warning := range g.Metadata.Warnings {
-		warningLower := strings.ToLower(warning)
-		for _, keyword := range warrantyKeywords {
-			if strings.Contains(warningLower, keyword) {
-				return true
-			}
-		}
-	}
-
-	// Проверка в названии
-	nameLower := strings.ToLower(g.Name)
-	for _, keyword := range warrantyKeywords {
-		if strings.Contains(nameLower, keyword) {
-			return true
-		}
-	}
-
-	return false
+	// Проверка в предупреждениях и названии
+	return hasKeyword(KeywordsWarranty, g.Metadata.Warnings...) ||
+		hasKeyword(KeywordsWarranty, g.Name)
 }
 
 // ValidateRequirementsCompliance проверяет соответствие подарка всем требованиям.
@@ -715,10 +302,10 @@ func (g *GiftItem) CanBeIncludedInGift(child *Child) (bool, []string) {
 			g.MinAge, child.Age)}
 	}
 
-	// Проверка особых заметок
+	// Проверка особых заметок. Предложения по заметкам готовит команда
+	// notes review, они применяются только после проверки человеком.
 	var warnings []string
 	if child.Notes != "" {
-		// Здесь можно добавить логику анализа заметок
 		warnings = append(warnings,
 			fmt.Sprintf("У ребенка есть особые заметки: %s", child.Notes))
 	}
@@ -762,12 +349,13 @@ func (g *GiftItem) GetComplianceSummary(reqs *SpecialRequirements) map[string]bo
 	return summary
 }
 
-// GetPriceWithCoefficient возвращает цену с учетом коэффициента региона.
-func (g *GiftItem) GetPriceWithCoefficient(coefficient float64) float64 {
+// GetPriceWithCoefficient возвращает цену с учетом коэффициента региона,
+// округленную до копейки (см. Money.MulCoefficient).
+func (g *GiftItem) GetPriceWithCoefficient(coefficient float64) Money {
 	if coefficient <= 0 {
 		coefficient = 1.0
 	}
-	return g.Price * coefficient
+	return g.Price.MulCoefficient(coefficient)
 }
 
 // GetWeightWithCoefficient возвращает вес с учетом коэффициента (если нужно).
@@ -793,7 +381,7 @@ func ValidateGiftItem(item GiftItem) error {
 	}
 
 	if item.Price < 0 {
-		return fmt.Errorf("цена не может быть отрицательной: %.2f", item.Price)
+		return fmt.Errorf("цена не может быть отрицательной: %s", item.Price)
 	}
 
 	if item.Weight < 0 {
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// GiftRecord - предмет, выданный ребенку в одном из прошлых лет.
+type GiftRecord struct {
+	ChildID  int    `json:"child_id" jsonschema:"required,minimum=1"`
+	Year     int    `json:"year" jsonschema:"required,minimum=1"`
+	ItemID   int    `json:"item_id" jsonschema:"required,minimum=1"`
+	ItemName string `json:"item_name,omitempty"`
+	Category string `json:"category" jsonschema:"required,minLength=1"`
+}
+
+// GiftHistory - история подарков прошлых лет.
+type GiftHistory struct {
+	Records []GiftRecord `json:"records" jsonschema:"required"`
+}
+
+// Import заменяет записи указанного года подарками из отчета.
+// Возвращает количество добавленных записей.
+func (h *GiftHistory) Import(year int, report Report) int {
+	h.Records = slices.DeleteFunc(h.Records, func(r GiftRecord) bool { return r.Year == year })
+
+	added := 0
+	for _, result := range report.Results {
+		for _, item := range result.GiftSelection {
+			h.Records = append(h.Records, GiftRecord{
+				ChildID:  result.ChildID,
+				Year:     year,
+				ItemID:   item.ItemID,
+				ItemName: item.ItemName,
+				Category: item.Category,
+			})
+			added++
+		}
+	}
+
+	sort.SliceStable(h.Records, func(i, j int) bool {
+		if h.Records[i].Year != h.Records[j].Year {
+			return h.Records[i].Year < h.Records[j].Year
+		}
+		return h.Records[i].ChildID < h.Records[j].ChildID
+	})
+	return added
+}
+
+// Years возвращает годы, для которых есть записи.
+func (h *GiftHistory) Years() []int {
+	var years []int
+	for _, r := range h.Records {
+		if !slices.Contains(years, r.Year) {
+			years = append(years, r.Year)
+		}
+	}
+	slices.Sort(years)
+	return years
+}
+
+// Past возвращает подарки ребенка за years лет до года year (не включая его).
+func (h *GiftHistory) Past(childID, year, years int) PastGifts {
+	past := PastGifts{Items: make(map[int]bool), Categories: make(map[string]bool)}
+	if h == nil {
+		return past
+	}
+
+	for _, r := range h.Records {
+		if r.ChildID != childID || r.Year >= year || r.Year < year-years {
+			continue
+		}
+		past.Items[r.ItemID] = true
+		past.Categories[r.Category] = true
+	}
+	return past
+}
+
+// PastGifts - предметы и категории, которые ребенок уже получал.
+type PastGifts struct {
+	Items      map[int]bool
+	Categories map[string]bool
+}
+
+// Empty сообщает, что ребенок ничего не получал.
+func (p PastGifts) Empty() bool {
+	return len(p.Items) == 0
+}
+
+// RepeatPolicy определяет отношение к повтору прошлогодних подарков.
+type RepeatPolicy string
+
+const (
+	// RepeatAllow - повторы не учитываются.
+	RepeatAllow RepeatPolicy = "allow"
+
+	// RepeatPenalize - повторы рассматриваются в последнюю очередь.
+	RepeatPenalize RepeatPolicy = "penalize"
+
+	// RepeatForbid - повторы исключаются.
+	RepeatForbid RepeatPolicy = "forbid"
+)
+
+// HistoryRules - правила учета истории подарков.
+type HistoryRules struct {
+	// Years - сколько прошлых лет учитывать.
+	Years int `json:"years" jsonschema:"minimum=1"`
+
+	// Items - политика для тех же предметов.
+	Items RepeatPolicy `json:"items,omitempty"`
+
+	// Categories - политика для тех же категорий.
+	Categories RepeatPolicy `json:"categories,omitempty"`
+}
+
+// Validate проверяет корректность правил.
+func (r *HistoryRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Years < 1 {
+		return fmt.Errorf("years должно быть не меньше 1: %d", r.Years)
+	}
+	for _, p := range []RepeatPolicy{r.Items, r.Categories} {
+		switch p {
+		case "", RepeatAllow, RepeatPenalize, RepeatForbid:
+		default:
+			return fmt.Errorf("неизвестная политика повторов: %s (допустимо: %s, %s, %s)",
+				p, RepeatAllow, RepeatPenalize, RepeatForbid)
+		}
+	}
+	return nil
+}
+
+// Repeats проверяет предмет на повтор. Возвращает политику, которая к нему
+// применяется, и причину; RepeatAllow означает, что повтора нет.
+func (r *HistoryRules) Repeats(past PastGifts, itemID int, category string) (RepeatPolicy, string) {
+	if r == nil {
+		return RepeatAllow, ""
+	}
+
+	policy, reason := RepeatAllow, ""
+	if past.Categories[category] && r.Categories != "" && r.Categories != RepeatAllow {
+		policy = r.Categories
+		reason = fmt.Sprintf("Категорию %s ребенок уже получал (учитывается лет: %d)", category, r.Years)
+	}
+	if past.Items[itemID] && r.Items != "" && r.Items != RepeatAllow && policy != RepeatForbid {
+		policy = r.Items
+		reason = fmt.Sprintf("Предмет ребенок уже получал (учитывается лет: %d)", r.Years)
+	}
+	return policy, reason
+}
+
+// HistorySummary - сводка по повторам подарков относительно прошлых лет.
+type HistorySummary struct {
+	Years                int `json:"years"`
+	ChildrenWithHistory  int `json:"children_with_history"`
+	ChildrenWithNewItems int `json:"children_with_new_items"`
+	RepeatedItems        int `json:"repeated_items"`
+}
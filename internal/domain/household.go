@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// HouseholdRules описывает подбор подарков для детей одной семьи (см. Child.FamilyID).
+type HouseholdRules struct {
+	// ValueTolerance - допустимое превышение стоимости подарка над самым дешевым
+	// непустым подарком семьи в долях (0.2 - на 20%). 0 - стоимость не выравнивается.
+	ValueTolerance float64 `json:"value_tolerance,omitempty" jsonschema:"minimum=0"`
+
+	// SharedCategories - категории, из которых семья получает один общий предмет
+	// вместо предмета каждому ребенку, например настольная игра.
+	SharedCategories []string `json:"shared_categories,omitempty"`
+}
+
+// Validate проверяет корректность правил.
+func (r *HouseholdRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.ValueTolerance < 0 {
+		return fmt.Errorf("value_tolerance не может быть отрицательным: %.2f", r.ValueTolerance)
+	}
+
+	seen := make(map[string]bool, len(r.SharedCategories))
+	for _, category := range r.SharedCategories {
+		if category == "" {
+			return fmt.Errorf("пустая категория в shared_categories")
+		}
+		if seen[category] {
+			return fmt.Errorf("категория %s указана в shared_categories повторно", category)
+		}
+		seen[category] = true
+	}
+
+	return nil
+}
+
+// Shared сообщает, выдается ли категория один раз на семью.
+func (r *HouseholdRules) Shared(category string) bool {
+	return r != nil && slices.Contains(r.SharedCategories, category)
+}
+
+// Tolerance возвращает допустимое отклонение стоимости подарков.
+func (r *HouseholdRules) Tolerance() float64 {
+	if r == nil {
+		return 0
+	}
+	return r.ValueTolerance
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Наборы ключевых слов, используемые эвристиками соответствия требованиям.
+const (
+	KeywordsPork                 = "pork"
+	KeywordsHalal                = "halal"
+	KeywordsKosher               = "kosher"
+	KeywordsNonKosher            = "non_kosher"
+	KeywordsAllergens            = "allergens"
+	KeywordsSafetyCertification  = "safety_certification"
+	KeywordsBPA                  = "bpa"
+	KeywordsFocus                = "focus"
+	KeywordsAutismFriendly       = "autism_friendly"
+	KeywordsWheelchairAccessible = "wheelchair_accessible"
+	KeywordsRecycled             = "recycled"
+	KeywordsEducational          = "educational"
+	KeywordsGenderMale           = "gender_male"
+	KeywordsGenderFemale         = "gender_female"
+	KeywordsWarranty             = "warranty"
+)
+
+// KeywordDictionary - словарь ключевых слов: набор -> язык -> слова.
+// Слова сравниваются по основам (см. Stem), фраза из нескольких слов должна
+// встретиться целиком. Слово со звездочкой на конце ("эко*") совпадает
+// с любым словом, которое с него начинается.
+type KeywordDictionary struct {
+	Version string                         `json:"version" jsonschema:"required,minLength=1"`
+	Sets    map[string]map[string][]string `json:"sets" jsonschema:"required"`
+}
+
+//go:embed keywords.json
+var builtinKeywords []byte
+
+var (
+	keywordsMu sync.RWMutex
+	keywords   = mustCompileKeywords(builtinKeywords)
+)
+
+// BuiltinKeywords возвращает встроенный словарь ключевых слов.
+func BuiltinKeywords() KeywordDictionary {
+	var d KeywordDictionary
+	if err := json.Unmarshal(builtinKeywords, &d); err != nil {
+		panic(fmt.Sprintf("встроенный словарь ключевых слов: %v", err))
+	}
+	return d
+}
+
+// Override возвращает словарь, в котором языки наборов из override заменяют
+// соответствующие языки текущего словаря. Новые наборы и языки добавляются.
+func (d KeywordDictionary) Override(override KeywordDictionary) KeywordDictionary {
+	result := KeywordDictionary{Version: d.Version, Sets: make(map[string]map[string][]string, len(d.Sets))}
+	for set, langs := range d.Sets {
+		result.Sets[set] = make(map[string][]string, len(langs))
+		for lang, words := range langs {
+			result.Sets[set][lang] = words
+		}
+	}
+
+	if override.Version != "" {
+		result.Version = d.Version + "+" + override.Version
+	}
+	for set, langs := range override.Sets {
+		if result.Sets[set] == nil {
+			result.Sets[set] = make(map[string][]string, len(langs))
+		}
+		for lang, words := range langs {
+			result.Sets[set][lang] = words
+		}
+	}
+	return result
+}
+
+// UseKeywords делает словарь текущим для эвристик GiftItem.
+func UseKeywords(d KeywordDictionary) {
+	compiled := compileKeywords(d)
+
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+	keywords = compiled
+}
+
+// KeywordsVersion возвращает версию текущего словаря.
+func KeywordsVersion() string {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+	return keywords.version
+}
+
+// KeywordSets возвращает названия наборов текущего словаря.
+func KeywordSets() []string {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+
+	names := make([]string, 0, len(keywords.sets))
+	for name := range keywords.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MatchKeywords возвращает ключевые слова набора, найденные в текстах.
+func MatchKeywords(set string, texts ...string) []string {
+	keywordsMu.RLock()
+	phrases := keywords.sets[set]
+	keywordsMu.RUnlock()
+
+	var matched []string
+	for _, text := range texts {
+		stems := Stems(text)
+		for _, p := range phrases {
+			if p.in(stems) {
+				matched = append(matched, p.keyword)
+			}
+		}
+	}
+	return matched
+}
+
+// hasKeyword сообщает, встречается ли в текстах хотя бы одно слово набора.
+func hasKeyword(set string, texts ...string) bool {
+	return len(MatchKeywords(set, texts...)) > 0
+}
+
+type compiledKeywords struct {
+	version string
+	sets    map[string][]keywordPhrase
+}
+
+// keywordPhrase - ключевое слово, разобранное на основы.
+type keywordPhrase struct {
+	keyword string
+	stems   []string
+	prefix  bool
+}
+
+// in проверяет, встречается ли фраза в последовательности основ.
+func (p keywordPhrase) in(stems []string) bool {
+	n := len(p.stems)
+	for start := 0; start+n <= len(stems); start++ {
+		ok := true
+		for i, s := range p.stems {
+			word := stems[start+i]
+			last := i == n-1
+			if word != s && !(last && p.prefix && strings.HasPrefix(word, s)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func mustCompileKeywords(data []byte) compiledKeywords {
+	var d KeywordDictionary
+	if err := json.Unmarshal(data, &d); err != nil {
+		panic(fmt.Sprintf("встроенный словарь ключевых слов: %v", err))
+	}
+	return compileKeywords(d)
+}
+
+func compileKeywords(d KeywordDictionary) compiledKeywords {
+	c := compiledKeywords{version: d.Version, sets: make(map[string][]keywordPhrase, len(d.Sets))}
+	for set, langs := range d.Sets {
+		for _, words := range langs {
+			for _, word := range words {
+				p := keywordPhrase{keyword: word}
+				text := word
+				if strings.HasSuffix(text, "*") {
+					p.prefix = true
+					text = strings.TrimSuffix(text, "*")
+				}
+
+				if p.prefix {
+					// Основа для префикса берется без отбрасывания окончания
+					p.stems = Tokenize(text)
+				} else {
+					p.stems = Stems(text)
+				}
+				if len(p.stems) > 0 {
+					c.sets[set] = append(c.sets[set], p)
+				}
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+// TestStem проверяет выделение основ русских и английских слов.
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"машинки", "машинк"},
+		{"роботами", "робот"},
+		{"cars", "car"},
+		{"cares", "care"},
+		{"cared", "cared"},
+		{"caring", "caring"},
+		{"painted", "paint"},
+		{"painting", "paint"},
+		{"boxes", "box"},
+		{"watches", "watch"},
+		{"puzzles", "puzzle"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, ожидалось %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+// TestKeywordsNoFalseStems проверяет, что слова, совпадающие с ключевым
+// словом только после слишком короткой основы, не находятся.
+func TestKeywordsNoFalseStems(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Racing car", true},
+		{"Toy cars", true},
+		{"Cartoon stickers", false},
+		{"Who cares", false},
+		{"Cared for by hand", false},
+		{"Caring bear", false},
+	}
+	for _, tt := range tests {
+		if got := hasKeyword(KeywordsGenderMale, tt.text); got != tt.want {
+			t.Errorf("%q: получено %v, ожидалось %v (совпадения: %v)",
+				tt.text, got, tt.want, MatchKeywords(KeywordsGenderMale, tt.text))
+		}
+	}
+}
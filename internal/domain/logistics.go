@@ -0,0 +1,87 @@
+package domain
+
+import "fmt"
+
+// LoadCapacity - вместимость одного рейса саней или грузовика.
+type LoadCapacity struct {
+	Name      string  `json:"name,omitempty"`
+	MaxWeight float64 `json:"max_weight" jsonschema:"required,exclusiveMinimum=0"`
+	MaxVolume float64 `json:"max_volume" jsonschema:"required,exclusiveMinimum=0"`
+}
+
+// LogisticsRules - параметры планирования загрузки.
+type LogisticsRules struct {
+	Vehicle LoadCapacity `json:"vehicle"`
+
+	// BoxVolume - минимальный объем коробки с подарком ребенка, л.
+	// Используется, если объем предметов в каталоге не указан.
+	BoxVolume float64 `json:"box_volume,omitempty" jsonschema:"minimum=0"`
+
+	// Depot - координаты склада, откуда начинается маршрут.
+	Depot *Coordinates `json:"depot,omitempty"`
+}
+
+// DefaultLogisticsRules - параметры загрузки, если они не заданы в конфигурации.
+var DefaultLogisticsRules = LogisticsRules{
+	Vehicle:   LoadCapacity{Name: "сани", MaxWeight: 300, MaxVolume: 2000},
+	BoxVolume: 3,
+}
+
+// Validate проверяет вместимость и объем коробки.
+func (r *LogisticsRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Vehicle.MaxWeight <= 0 || r.Vehicle.MaxVolume <= 0 {
+		return fmt.Errorf("вместимость рейса должна быть положительной")
+	}
+	if r.BoxVolume < 0 {
+		return fmt.Errorf("объем коробки не может быть отрицательным: %.2f", r.BoxVolume)
+	}
+	return nil
+}
+
+// ChildBox - коробка с подарком одного ребенка.
+type ChildBox struct {
+	ChildID   int     `json:"child_id"`
+	ChildName string  `json:"child_name"`
+	Region    string  `json:"region"`
+	Weight    float64 `json:"weight"`
+	Volume    float64 `json:"volume"`
+}
+
+// Load - один рейс: коробки одного региона и загрузка относительно вместимости.
+type Load struct {
+	Number            int        `json:"number"`
+	Region            string     `json:"region"`
+	Boxes             []ChildBox `json:"boxes"`
+	Weight            float64    `json:"weight"`
+	Volume            float64    `json:"volume"`
+	WeightUtilization float64    `json:"weight_utilization"`
+	VolumeUtilization float64    `json:"volume_utilization"`
+}
+
+// Add кладет коробку в рейс.
+func (l *Load) Add(box ChildBox) {
+	l.Boxes = append(l.Boxes, box)
+	l.Weight += box.Weight
+	l.Volume += box.Volume
+}
+
+// RouteStop - регион маршрута и расстояние от предыдущей точки, км.
+type RouteStop struct {
+	Order    int          `json:"order"`
+	Region   string       `json:"region"`
+	Location *Coordinates `json:"location,omitempty"`
+	Distance float64      `json:"distance,omitempty"`
+	Loads    int          `json:"loads"`
+}
+
+// LoadManifest - план загрузки: маршрут, рейсы и коробки, не помещающиеся в рейс.
+type LoadManifest struct {
+	Vehicle   LoadCapacity `json:"vehicle"`
+	Route     []RouteStop  `json:"route"`
+	Loads     []Load       `json:"loads"`
+	Oversized []ChildBox   `json:"oversized,omitempty"`
+	Distance  float64      `json:"distance,omitempty"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+// Стратегии подбора подарков.
+const (
+	// StrategyCatalogOrder - предметы перебираются в порядке каталога.
+	StrategyCatalogOrder = "catalog_order"
+
+	// StrategyCheapestFirst - сначала самые дешевые, чтобы вместить больше позиций.
+	StrategyCheapestFirst = "cheapest_first"
+)
+
+// Режимы распределения предметов между детьми.
+const (
+	// AllocationUnlimited - остатки на складе не учитываются.
+	AllocationUnlimited = "unlimited"
+
+	// AllocationStock - подбор ведется из общего ограниченного запаса.
+	AllocationStock = "stock"
+)
+
+// Режимы подбора подарка.
+const (
+	// ModeIndividual - подарок собирается для каждого ребенка индивидуально.
+	ModeIndividual = "individual"
+
+	// ModeTemplate - подарок собирается по стандартному набору возрастной группы.
+	ModeTemplate = "template"
+)
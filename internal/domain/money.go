@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money - денежная сумма в минимальных единицах валюты (копейках).
+//
+// В JSON сумма записывается десятичным числом, как раньше записывались
+// цены float64: 899.99, 1000. При чтении число разбирается точно, без
+// промежуточного float64; дробная часть сверх копеек округляется
+// до ближайшей копейки, половина - от нуля.
+type Money int64
+
+// MinorUnits - число минимальных единиц в основной единице валюты.
+const MinorUnits = 100
+
+// NewMoney переводит сумму в основных единицах в Money с округлением до копейки
+// (половина - от нуля). Используется для значений, уже полученных как float64.
+func NewMoney(amount float64) Money {
+	return Money(math.Round(amount * MinorUnits))
+}
+
+// ParseMoney разбирает десятичную запись суммы ("1000", "899.99", "1e3").
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("некорректная сумма: %q", s)
+	}
+	return moneyFromRat(r)
+}
+
+func moneyFromRat(r *big.Rat) (Money, error) {
+	r = new(big.Rat).Mul(r, big.NewRat(MinorUnits, 1))
+
+	// Округление до целого, половина - от нуля
+	num, den := r.Num(), r.Denom()
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("сумма вне допустимого диапазона: %s", r.FloatString(2))
+	}
+	return Money(q.Int64()), nil
+}
+
+// Float возвращает сумму в основных единицах для процентов и отображения.
+func (m Money) Float() float64 {
+	return float64(m) / MinorUnits
+}
+
+// MulCoefficient умножает сумму на коэффициент (региональный, курс валюты)
+// и округляет результат до копейки, половина - от нуля.
+// Коэффициент переводится в точную дробь, поэтому 1.15 * 100.00 дает ровно 115.00.
+func (m Money) MulCoefficient(c float64) Money {
+	coef, ok := new(big.Rat).SetString(strconv.FormatFloat(c, 'f', -1, 64))
+	if !ok {
+		return NewMoney(m.Float() * c)
+	}
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(m)), coef)
+	result, err := moneyFromRat(r.Quo(r, big.NewRat(MinorUnits, 1)))
+	if err != nil {
+		return NewMoney(m.Float() * c)
+	}
+	return result
+}
+
+// Div делит сумму на n частей с округлением до копейки; для n <= 0 возвращает 0.
+func (m Money) Div(n int) Money {
+	if n <= 0 {
+		return 0
+	}
+	result, _ := moneyFromRat(big.NewRat(int64(m), int64(n)*MinorUnits))
+	return result
+}
+
+// Ratio возвращает отношение сумм; для нулевого делителя возвращает 0.
+func (m Money) Ratio(total Money) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(m) / float64(total)
+}
+
+// String возвращает сумму с двумя знаками после точки: "899.99", "-12.50".
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/MinorUnits, v%MinorUnits)
+}
+
+// MarshalJSON записывает сумму десятичным числом без лишних нулей: 1000, 520.5, 899.99.
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := strings.TrimRight(strings.TrimRight(m.String(), "0"), ".")
+	if s == "" || s == "-" {
+		s = "0"
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON читает сумму из десятичного числа.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Abs возвращает модуль суммы.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// LogValue записывает сумму в журнал числом в основных единицах.
+func (m Money) LogValue() slog.Value {
+	return slog.Float64Value(m.Float())
+}
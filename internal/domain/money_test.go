@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseMoney проверяет разбор десятичной записи и округление
+// до копейки: половина - от нуля.
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"1000", 100000},
+		{"899.99", 89999},
+		{"1e3", 100000},
+		{"1.005", 101},
+		{"-1.005", -101},
+		{"2.675", 268},
+		{"-2.675", -268},
+		{"1.004", 100},
+		{"-1.004", -100},
+		{" 0.1 ", 10},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, ожидалось %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestParseMoneyInvalid проверяет отказ для нечисловой записи и сумм вне диапазона int64.
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1,5", "1e30", "-1e30"} {
+		if _, err := ParseMoney(in); err == nil {
+			t.Errorf("ParseMoney(%q) должна вернуть ошибку", in)
+		}
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte("1e30"), &m); err == nil {
+		t.Error("сумма вне диапазона в JSON должна отклоняться")
+	}
+}
+
+// TestMoneyMulCoefficient проверяет, что коэффициент умножается точно.
+func TestMoneyMulCoefficient(t *testing.T) {
+	tests := []struct {
+		m    Money
+		c    float64
+		want Money
+	}{
+		{10000, 1.15, 11500},
+		{10000, 1.1, 11000},
+		{89999, 1.5, 134999},
+		{101, 0.5, 51},
+		{-101, 0.5, -51},
+		{12345, 1, 12345},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulCoefficient(tt.c); got != tt.want {
+			t.Errorf("%s * %g = %s, ожидалось %s", tt.m, tt.c, got, tt.want)
+		}
+	}
+}
+
+// TestMoneyDiv проверяет округление остатка при делении.
+func TestMoneyDiv(t *testing.T) {
+	tests := []struct {
+		m    Money
+		n    int
+		want Money
+	}{
+		{10000, 3, 3333},
+		{20000, 3, 6667},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{100, 0, 0},
+		{100, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Div(tt.n); got != tt.want {
+			t.Errorf("%s / %d = %s, ожидалось %s", tt.m, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestMoneyJSON проверяет, что суммы читаются и записываются теми же
+// десятичными числами, что и прежние цены float64.
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"899.99", "899.99"},
+		{"1e3", "1000"},
+		{"520.50", "520.5"},
+		{"0", "0"},
+		{"-12.5", "-12.5"},
+	}
+	for _, tt := range tests {
+		var m Money
+		if err := json.Unmarshal([]byte(tt.in), &m); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Errorf("Marshal(%s): %v", tt.in, err)
+			continue
+		}
+		if string(data) != tt.out {
+			t.Errorf("%s -> %s, ожидалось %s", tt.in, data, tt.out)
+		}
+	}
+
+	m := Money(777)
+	if err := json.Unmarshal([]byte("null"), &m); err != nil || m != 777 {
+		t.Errorf("null не должен менять сумму: %s, %v", m, err)
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotePattern - шаблон словаря заметок: фраза и предложение, которое она означает.
+type NotePattern struct {
+	// Phrase - ключевые слова шаблона, сравниваются по основам.
+	// Между словами в заметке допускается до NotesDictionary.MaxGap лишних слов.
+	Phrase string `json:"phrase" jsonschema:"required,minLength=1"`
+
+	// Lang - язык шаблона (ru, en), используется для справки.
+	Lang string `json:"lang,omitempty"`
+
+	// Предлагаемое требование; заполняется одно из полей.
+	Dietary DietaryRequirement `json:"dietary,omitempty"`
+	Safety  SafetyRequirement  `json:"safety,omitempty"`
+	Medical MedicalRequirement `json:"medical,omitempty"`
+	Other   OtherRequirement   `json:"other,omitempty"`
+
+	// Ban - слово, по которому запрещаются предметы каталога, например "шоколад".
+	Ban string `json:"ban,omitempty"`
+
+	// BanCategory - категория каталога, предметы которой запрещаются, например "sweets".
+	BanCategory string `json:"ban_category,omitempty"`
+
+	// Confidence - уверенность при точном совпадении слов, от 0 до 1.
+	Confidence float64 `json:"confidence" jsonschema:"required,minimum=0,maximum=1"`
+}
+
+// Requirement возвращает категорию и значение предлагаемого требования
+// в терминах SpecialRequirements.HasRequirement или пустые строки для запрета предметов.
+func (p NotePattern) Requirement() (category, value string) {
+	switch {
+	case p.Dietary != "":
+		return "dietary", string(p.Dietary)
+	case p.Safety != "":
+		return "safety", string(p.Safety)
+	case p.Medical != "":
+		return "medical", string(p.Medical)
+	case p.Other != "":
+		return "other", string(p.Other)
+	}
+	return "", ""
+}
+
+// NotesDictionary - редактируемый словарь для разбора заметок о детях.
+type NotesDictionary struct {
+	Version string `json:"version" jsonschema:"required,minLength=1"`
+
+	// Negations - слова отрицания: "нет аллергии на орехи" не дает предложения.
+	Negations []string `json:"negations"`
+
+	// MaxGap - сколько посторонних слов допускается между словами фразы.
+	MaxGap int `json:"max_gap,omitempty" jsonschema:"minimum=0"`
+
+	Patterns []NotePattern `json:"patterns" jsonschema:"required"`
+}
+
+// Validate проверяет словарь.
+func (d *NotesDictionary) Validate() error {
+	for i, p := range d.Patterns {
+		if strings.TrimSpace(p.Phrase) == "" {
+			return fmt.Errorf("шаблон %d: пустая фраза", i)
+		}
+
+		proposals := 0
+		for _, set := range []bool{p.Dietary != "", p.Safety != "", p.Medical != "", p.Other != "", p.Ban != "" || p.BanCategory != ""} {
+			if set {
+				proposals++
+			}
+		}
+		if proposals != 1 {
+			return fmt.Errorf("шаблон %q: нужно указать ровно одно предложение (требование или ban)", p.Phrase)
+		}
+	}
+	return nil
+}
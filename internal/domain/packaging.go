@@ -0,0 +1,67 @@
+package domain
+
+import "fmt"
+
+// Dimensions - габариты предмета или внутренние размеры коробки, см.
+type Dimensions struct {
+	Length float64 `json:"length" jsonschema:"required,exclusiveMinimum=0"`
+	Width  float64 `json:"width" jsonschema:"required,exclusiveMinimum=0"`
+	Height float64 `json:"height" jsonschema:"required,exclusiveMinimum=0"`
+}
+
+// Volume возвращает объем, л.
+func (d Dimensions) Volume() float64 {
+	return d.Length * d.Width * d.Height / 1000
+}
+
+// Rotations возвращает все шесть ориентаций габаритов.
+func (d Dimensions) Rotations() []Dimensions {
+	l, w, h := d.Length, d.Width, d.Height
+	return []Dimensions{
+		{l, w, h}, {l, h, w},
+		{w, l, h}, {w, h, l},
+		{h, l, w}, {h, w, l},
+	}
+}
+
+func (d Dimensions) String() string {
+	return fmt.Sprintf("%g×%g×%g", d.Length, d.Width, d.Height)
+}
+
+// BoxSize - стандартный размер коробки для подарка.
+type BoxSize struct {
+	Name  string     `json:"name" jsonschema:"required,minLength=1"`
+	Inner Dimensions `json:"inner"`
+
+	// MaxWeight - допустимый вес содержимого, кг; 0 - без ограничения.
+	MaxWeight float64 `json:"max_weight,omitempty" jsonschema:"minimum=0"`
+}
+
+// StandardBoxes - размеры коробок, если они не заданы в конфигурации.
+var StandardBoxes = []BoxSize{
+	{Name: "S", Inner: Dimensions{Length: 20, Width: 15, Height: 10}, MaxWeight: 2},
+	{Name: "M", Inner: Dimensions{Length: 30, Width: 20, Height: 15}, MaxWeight: 5},
+	{Name: "L", Inner: Dimensions{Length: 40, Width: 30, Height: 20}, MaxWeight: 10},
+	{Name: "XL", Inner: Dimensions{Length: 60, Width: 40, Height: 30}, MaxWeight: 20},
+}
+
+// ValidateBoxes проверяет размеры коробок и уникальность названий.
+func ValidateBoxes(boxes []BoxSize) error {
+	seen := make(map[string]bool, len(boxes))
+	for _, b := range boxes {
+		if b.Name == "" {
+			return fmt.Errorf("не указано название коробки")
+		}
+		if seen[b.Name] {
+			return fmt.Errorf("коробка %s указана дважды", b.Name)
+		}
+		seen[b.Name] = true
+		if b.Inner.Length <= 0 || b.Inner.Width <= 0 || b.Inner.Height <= 0 {
+			return fmt.Errorf("коробка %s: размеры должны быть положительными", b.Name)
+		}
+		if b.MaxWeight < 0 {
+			return fmt.Errorf("коробка %s: допустимый вес не может быть отрицательным", b.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AgeSafetyRule - требования безопасности, обязательные для детей указанного возраста.
+type AgeSafetyRule struct {
+	// MinAge и MaxAge - границы возраста включительно.
+	MinAge int `json:"min_age" jsonschema:"minimum=0"`
+	MaxAge int `json:"max_age" jsonschema:"minimum=0"`
+
+	// Safety - требования, добавляемые ребенку автоматически.
+	Safety []SafetyRequirement `json:"safety" jsonschema:"required"`
+
+	// Reason - основание правила для примечаний в отчете.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Applies сообщает, относится ли правило к ребенку данного возраста.
+func (r AgeSafetyRule) Applies(age int) bool {
+	return age >= r.MinAge && age <= r.MaxAge
+}
+
+// MandatorySafetyRules - правила, которые действуют всегда и не отключаются настройками.
+var MandatorySafetyRules = []AgeSafetyRule{
+	{
+		MinAge: 0,
+		MaxAge: 3,
+		Safety: []SafetyRequirement{SafetyNoSmallParts},
+		Reason: "детали > 3см для детей до 3 лет",
+	},
+}
+
+// SafetyPolicy - возрастная политика безопасности. Дополнительные правила
+// из настроек действуют вместе с обязательными (MandatorySafetyRules).
+type SafetyPolicy struct {
+	Rules []AgeSafetyRule `json:"rules,omitempty"`
+}
+
+// Validate проверяет корректность правил.
+func (p *SafetyPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	known := GetAllRequirements()["safety"]
+	for i, r := range p.Rules {
+		if r.MinAge < 0 || r.MaxAge < r.MinAge {
+			return fmt.Errorf("правило %d: некорректный возраст %d-%d", i+1, r.MinAge, r.MaxAge)
+		}
+		if len(r.Safety) == 0 {
+			return fmt.Errorf("правило %d: не указаны требования безопасности", i+1)
+		}
+		for _, req := range r.Safety {
+			if !slices.Contains(known, string(req)) {
+				return fmt.Errorf("правило %d: недопустимое требование безопасности: %s", i+1, req)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AllRules возвращает обязательные правила и правила из настроек.
+func (p *SafetyPolicy) AllRules() []AgeSafetyRule {
+	rules := slices.Clone(MandatorySafetyRules)
+	if p != nil {
+		rules = append(rules, p.Rules...)
+	}
+	return rules
+}
+
+// Apply возвращает ребенка с требованиями безопасности, дополненными
+// правилами политики, добавленные требования и примечания о правилах,
+// которые что-то добавили. Явные требования ребенка сохраняются:
+// политика только добавляет требования.
+func (p *SafetyPolicy) Apply(child Child) (Child, []SafetyRequirement, []string) {
+	var notes []string
+	var added []SafetyRequirement
+	for _, rule := range p.AllRules() {
+		if !rule.Applies(child.Age) {
+			continue
+		}
+
+		var names []string
+		for _, req := range rule.Safety {
+			if !child.SpecialRequirements.HasRequirement("safety", string(req)) && !slices.Contains(added, req) {
+				added = append(added, req)
+				names = append(names, string(req))
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		note := fmt.Sprintf("Политика безопасности для возраста %d-%d: %s", rule.MinAge, rule.MaxAge, strings.Join(names, ", "))
+		if rule.Reason != "" {
+			note += " (" + rule.Reason + ")"
+		}
+		notes = append(notes, note)
+	}
+
+	if len(added) == 0 {
+		return child, nil, nil
+	}
+
+	reqs := SpecialRequirements{}
+	if child.SpecialRequirements != nil {
+		reqs = *child.SpecialRequirements
+	}
+	reqs.Safety = append(slices.Clone(reqs.Safety), added...)
+	child.SpecialRequirements = &reqs
+	return child, added, notes
+}
@@ -1,8 +1,67 @@
 package domain
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 type Region struct {
-	Name        string  `json:"name"`
-	Coefficient float64 `json:"coefficient"`
+	Name        string  `json:"name" jsonschema:"required,minLength=1"`
+	Coefficient float64 `json:"coefficient" jsonschema:"required,exclusiveMinimum=0"`
+
+	// Delivery - тариф доставки в регион; nil - действует тариф по умолчанию.
+	Delivery *DeliveryTariff `json:"delivery,omitempty"`
+
+	// Location - координаты пункта выдачи подарков региона.
+	Location *Coordinates `json:"location,omitempty"`
+
+	// Order - порядок региона в маршруте доставки, 0 - после упорядоченных.
+	Order int `json:"order,omitempty" jsonschema:"minimum=0"`
+
+	// TimeZone - часовой пояс региона (IANA), например "Asia/Yakutsk".
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// Zone возвращает часовой пояс региона или fallback, если пояс не задан.
+func (r Region) Zone(fallback *time.Location) (*time.Location, error) {
+	if r.TimeZone == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("регион %s: неизвестный часовой пояс %q", r.Name, r.TimeZone)
+	}
+	return loc, nil
+}
+
+// NewYear возвращает момент наступления Нового года (полночь 1 января
+// года year+1) по местному времени региона.
+func (r Region) NewYear(year int, fallback *time.Location) (time.Time, error) {
+	loc, err := r.Zone(fallback)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc), nil
+}
+
+// Coordinates - географические координаты в градусах.
+type Coordinates struct {
+	Latitude  float64 `json:"latitude" jsonschema:"minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"minimum=-180,maximum=180"`
+}
+
+// earthRadius - средний радиус Земли, км.
+const earthRadius = 6371.0
+
+// DistanceTo возвращает расстояние по дуге большого круга до точки, км.
+func (c Coordinates) DistanceTo(to Coordinates) float64 {
+	lat1, lat2 := c.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - c.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
 }
 
 type RegionRepository interface {
@@ -6,21 +6,27 @@ import (
 
 // Report представляет полный отчет о расчете подарков.
 type Report struct {
-	Version          string           `json:"version"`
-	GeneratedAt      time.Time        `json:"generated_at"`
-	Results          []ChildResult    `json:"results"`
-	AgeGroupAnalysis AgeGroupAnalysis `json:"age_group_analysis,omitempty"`
+	Version          string             `json:"version"`
+	GeneratedAt      time.Time          `json:"generated_at"`
+	Results          []ChildResult      `json:"results"`
+	AgeGroupAnalysis AgeGroupAnalysis   `json:"age_group_analysis,omitempty"`
+	StockAllocation  *StockAllocation   `json:"stock_allocation,omitempty"`
+	TemplateUsage    []TemplateUsage    `json:"template_usage,omitempty"`
+	Households       []HouseholdSummary `json:"households,omitempty"`
+	HistorySummary   *HistorySummary    `json:"history_summary,omitempty"`
+	Currency         *ReportCurrency    `json:"currency,omitempty"`
+	RegionAnalysis   []RegionAnalysis   `json:"region_analysis,omitempty"`
 }
 
 // ReportParameters содержит параметры запуска расчета.
 type ReportParameters struct {
-	ChildrenFile         string  `json:"children_file"`
-	CatalogFile          string  `json:"catalog_file"`
-	WishesFile           string  `json:"wishes_file,omitempty"`
-	RegionsFile          string  `json:"regions_file,omitempty"`
-	MaxGiftPrice         float64 `json:"max_gift_price,omitempty"`
-	TotalBudget          float64 `json:"total_budget,omitempty"`
-	ConsiderRequirements bool    `json:"consider_requirements"`
+	ChildrenFile         string `json:"children_file"`
+	CatalogFile          string `json:"catalog_file"`
+	WishesFile           string `json:"wishes_file,omitempty"`
+	RegionsFile          string `json:"regions_file,omitempty"`
+	MaxGiftPrice         Money  `json:"max_gift_price,omitempty"`
+	TotalBudget          Money  `json:"total_budget,omitempty"`
+	ConsiderRequirements bool   `json:"consider_requirements"`
 }
 
 // ReportStatistics содержит статистику расчета.
@@ -29,11 +35,11 @@ type ReportStatistics struct {
 	SuccessfulCalculations int                    `json:"successful_calculations"`
 	FailedCalculations     int                    `json:"failed_calculations"`
 	ProcessingTimeMs       int64                  `json:"processing_time_ms"`
-	TotalCost              float64                `json:"total_cost"`
+	TotalCost              Money                  `json:"total_cost"`
 	TotalWeight            float64                `json:"total_weight"`
-	AverageCostPerChild    float64                `json:"average_cost_per_child"`
-	MinGiftCost            float64                `json:"min_gift_cost"`
-	MaxGiftCost            float64                `json:"max_gift_cost"`
+	AverageCostPerChild    Money                  `json:"average_cost_per_child"`
+	MinGiftCost            Money                  `json:"min_gift_cost"`
+	MaxGiftCost            Money                  `json:"max_gift_cost"`
 	AverageItemsPerGift    float64                `json:"average_items_per_gift"`
 	BudgetUsagePercentage  float64                `json:"budget_usage_percentage"`
 	RequirementsStatistics RequirementsStatistics `json:"requirements_statistics"`
@@ -56,11 +62,42 @@ type ChildResult struct {
 	Age                 int                  `json:"age"`
 	Region              string               `json:"region"`
 	SpecialRequirements *SpecialRequirements `json:"special_requirements,omitempty"`
-	GiftSelection       []GiftSelection      `json:"gift_selection"`
-	CostSummary         ChildCostSummary     `json:"cost_summary"`
-	SelectionNotes      []string             `json:"selection_notes,omitempty"`
-	Warnings            []string             `json:"warnings,omitempty"`
-	Errors              *string              `json:"errors,omitempty"`
+	// PolicyRequirements - требования безопасности, добавленные возрастной
+	// политикой сверх SpecialRequirements из входного файла.
+	PolicyRequirements []SafetyRequirement `json:"policy_requirements,omitempty"`
+	Template           string              `json:"template,omitempty"`
+	GiftSelection      []GiftSelection     `json:"gift_selection"`
+	CostSummary        ChildCostSummary    `json:"cost_summary"`
+	Box                string              `json:"box,omitempty"`
+	SelectionNotes     []string            `json:"selection_notes,omitempty"`
+	SelectionTrace     []SelectionDecision `json:"selection_trace,omitempty"`
+	Warnings           []string            `json:"warnings,omitempty"`
+	Errors             *string             `json:"errors,omitempty"`
+}
+
+// SelectionOutcome - итог рассмотрения предмета-кандидата при подборе.
+type SelectionOutcome string
+
+const (
+	OutcomeAccepted            SelectionOutcome = "accepted"
+	OutcomeRejectedAge         SelectionOutcome = "rejected_age"
+	OutcomeRejectedRequirement SelectionOutcome = "rejected_requirement"
+	OutcomeRejectedComposition SelectionOutcome = "rejected_composition"
+	OutcomeRejectedHistory     SelectionOutcome = "rejected_history"
+	OutcomeSkippedBudget       SelectionOutcome = "skipped_budget"
+	OutcomeSkippedCount        SelectionOutcome = "skipped_count"
+	OutcomeSkippedWeight       SelectionOutcome = "skipped_weight"
+	OutcomeSkippedStock        SelectionOutcome = "skipped_stock"
+)
+
+// SelectionDecision содержит решение по одному предмету-кандидату.
+// Заполняется только в режиме --explain.
+type SelectionDecision struct {
+	ItemID       int              `json:"item_id"`
+	ItemName     string           `json:"item_name"`
+	Outcome      SelectionOutcome `json:"outcome"`
+	Reason       string           `json:"reason,omitempty"`
+	Requirements []string         `json:"requirements,omitempty"`
 }
 
 // GiftSelection содержит информацию о выбранном предмете.
@@ -68,7 +105,7 @@ type GiftSelection struct {
 	ItemID          int                    `json:"item_id"`
 	ItemName        string                 `json:"item_name"`
 	Category        string                 `json:"category"`
-	Price           float64                `json:"price"`
+	Price           Money                  `json:"price"`
 	Weight          float64                `json:"weight"`
 	SelectionReason string                 `json:"selection_reason"`
 	ComplianceCheck map[string]bool        `json:"compliance_check"`
@@ -77,8 +114,14 @@ type GiftSelection struct {
 
 // ChildCostSummary содержит сводку по стоимости подарка.
 type ChildCostSummary struct {
-	Cost       float64 `json:"cost"`
+	Cost       Money   `json:"cost"`
+	Weight     float64 `json:"weight,omitempty"`
 	ItemsCount int     `json:"items_count"`
+
+	// Delivery - стоимость доставки подарка, Total - подарок вместе с доставкой.
+	// Заполняются, если задана модель доставки.
+	Delivery Money `json:"delivery,omitempty"`
+	Total    Money `json:"total,omitempty"`
 }
 
 // FailedCalculation содержит информацию о неудачном расчете.
@@ -106,6 +149,17 @@ type ProductionSummary struct {
 	TotalItemsNeeded    int                           `json:"total_items_needed"`
 	ItemsBreakdown      []ProductionItemBreakdown     `json:"items_breakdown"`
 	CategoriesBreakdown []ProductionCategoryBreakdown `json:"categories_breakdown"`
+	BoxesBreakdown      []ProductionBoxBreakdown      `json:"boxes_breakdown,omitempty"`
+
+	// Unboxed - подарки, для которых не нашлось стандартной коробки.
+	Unboxed []int `json:"unboxed,omitempty"`
+}
+
+// ProductionBoxBreakdown содержит потребность в коробках одного размера.
+type ProductionBoxBreakdown struct {
+	Box        string     `json:"box"`
+	Dimensions Dimensions `json:"dimensions"`
+	Quantity   int        `json:"quantity"`
 }
 
 // ProductionItemBreakdown содержит разбивку по предметам.
@@ -114,26 +168,26 @@ type ProductionItemBreakdown struct {
 	ItemName         string  `json:"item_name"`
 	Category         string  `json:"category"`
 	RequiredQuantity int     `json:"required_quantity"`
-	TotalCost        float64 `json:"total_cost"`
+	TotalCost        Money   `json:"total_cost"`
 	TotalWeight      float64 `json:"total_weight"`
 }
 
 // ProductionCategoryBreakdown содержит разбивку по категориям.
 type ProductionCategoryBreakdown struct {
-	CategoryID    string  `json:"category_id"`
-	CategoryName  string  `json:"category_name"`
-	ItemsCount    int     `json:"items_count"`
-	TotalQuantity int     `json:"total_quantity"`
-	TotalCost     float64 `json:"total_cost"`
+	CategoryID    string `json:"category_id"`
+	CategoryName  string `json:"category_name"`
+	ItemsCount    int    `json:"items_count"`
+	TotalQuantity int    `json:"total_quantity"`
+	TotalCost     Money  `json:"total_cost"`
 }
 
 // BudgetAnalysis содержит анализ бюджета.
 type BudgetAnalysis struct {
-	TotalBudget     float64  `json:"total_budget"`
-	TotalUsed       float64  `json:"total_used"`
-	RemainingBudget float64  `json:"remaining_budget"`
+	TotalBudget     Money    `json:"total_budget"`
+	TotalUsed       Money    `json:"total_used"`
+	RemainingBudget Money    `json:"remaining_budget"`
 	UsagePercentage float64  `json:"usage_percentage"`
-	PerChildAverage float64  `json:"per_child_average"`
+	PerChildAverage Money    `json:"per_child_average"`
 	BudgetStatus    string   `json:"budget_status"` // UNDER_BUDGET, WITHIN_BUDGET, OVER_BUDGET
 	Recommendations []string `json:"recommendations"`
 }
@@ -142,19 +196,23 @@ type BudgetAnalysis struct {
 type RegionAnalysis struct {
 	Region        string  `json:"region"`
 	ChildrenCount int     `json:"children_count"`
-	TotalCost     float64 `json:"total_cost"`
-	AverageCost   float64 `json:"average_cost"`
+	TotalCost     Money   `json:"total_cost"`
+	AverageCost   Money   `json:"average_cost"`
 	Coefficient   float64 `json:"coefficient"`
+
+	// TotalWeight и DeliveryCost - вес подарков региона и стоимость их доставки.
+	TotalWeight  float64 `json:"total_weight,omitempty"`
+	DeliveryCost Money   `json:"delivery_cost,omitempty"`
 }
 
 // AgeGroupAnalysis содержит анализ по возрастным группам.
 type AgeGroupAnalysis struct {
-	AgeGroup      string  `json:"age_group"`
-	MinAge        int     `json:"min_age"`
-	MaxAge        int     `json:"max_age"`
-	ChildrenCount int     `json:"children_count"`
-	TotalCost     float64 `json:"total_cost"`
-	AverageCost   float64 `json:"average_cost"`
+	AgeGroup      string `json:"age_group"`
+	MinAge        int    `json:"min_age"`
+	MaxAge        int    `json:"max_age"`
+	ChildrenCount int    `json:"children_count"`
+	TotalCost     Money  `json:"total_cost"`
+	AverageCost   Money  `json:"average_cost"`
 }
 
 // RequirementsAnalysis содержит анализ влияния требований.
@@ -167,16 +225,69 @@ type RequirementsAnalysis struct {
 
 // RequirementImpact содержит информацию о влиянии требования.
 type RequirementImpact struct {
-	AffectedChildren    int     `json:"affected_children"`
-	AverageCostIncrease float64 `json:"average_cost_increase"`
-	DifficultyLevel     string  `json:"difficulty_level"` // LOW, MEDIUM, HIGH
+	AffectedChildren    int    `json:"affected_children"`
+	AverageCostIncrease Money  `json:"average_cost_increase"`
+	DifficultyLevel     string `json:"difficulty_level"` // LOW, MEDIUM, HIGH
 }
 
 // OptimizationSuggestion содержит предложение по оптимизации.
 type OptimizationSuggestion struct {
 	Suggestion       string   `json:"suggestion"`
-	PotentialSaving  float64  `json:"potential_saving"`
+	PotentialSaving  Money    `json:"potential_saving"`
 	AffectedChildren []int    `json:"affected_children,omitempty"`
 	AffectedRegions  []string `json:"affected_regions,omitempty"`
 	Complexity       string   `json:"complexity"` // LOW, MEDIUM, HIGH
 }
+
+// StockAllocation содержит итоги распределения ограниченного складского запаса.
+type StockAllocation struct {
+	Items         []StockConsumption  `json:"items"`
+	Substitutions []StockSubstitution `json:"substitutions,omitempty"`
+}
+
+// StockConsumption содержит расход запаса по одному предмету.
+type StockConsumption struct {
+	ItemID    int    `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	Initial   int    `json:"initial"`
+	Consumed  int    `json:"consumed"`
+	Remaining int    `json:"remaining"`
+}
+
+// StockSubstitution описывает ребенка, получившего замену из-за нехватки запаса.
+type StockSubstitution struct {
+	ChildID        int    `json:"child_id"`
+	ChildName      string `json:"child_name"`
+	Region         string `json:"region"`
+	MissingItemIDs []int  `json:"missing_item_ids"`
+
+	// SubstituteItemIDs - замены в том же порядке, что и MissingItemIDs.
+	SubstituteItemIDs []int `json:"substitute_item_ids,omitempty"`
+}
+
+// TemplateUsage содержит сводку по использованию шаблона подарка.
+// Variants - число различных наборов предметов, которые придется собрать.
+type TemplateUsage struct {
+	Template string `json:"template"`
+	Children int    `json:"children"`
+	Variants int    `json:"variants"`
+}
+
+// HouseholdSummary содержит сводку подарков для одной семьи.
+type HouseholdSummary struct {
+	FamilyID  string `json:"family_id"`
+	ChildIDs  []int  `json:"child_ids"`
+	TotalCost Money  `json:"total_cost"`
+	MinCost   Money  `json:"min_cost"`
+	MaxCost   Money  `json:"max_cost"`
+
+	// Balanced - стоимость подарков укладывается в допустимое отклонение.
+	Balanced bool `json:"balanced"`
+
+	// SharedItems - общие предметы семьи. Они кладутся в подарок ребенка
+	// CarrierID и входят в его стоимость, вес, доставку и коробку;
+	// MinCost, MaxCost и Balanced считаются без них.
+	SharedItems []GiftSelection `json:"shared_items,omitempty"`
+	CarrierID   int             `json:"carrier_id,omitempty"`
+	Region      string          `json:"region,omitempty"`
+}
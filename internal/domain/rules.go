@@ -0,0 +1,450 @@
+package domain
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// RulesDictionary - определения требований в виде выражений над метаданными
+// предмета: категория -> требование -> выражение.
+//
+// В выражениях доступны поля GiftMetadata по JSON-именам (contains_nuts,
+// small_parts_size и т.д.), эвристики по ключевым словам (см. RuleHelpers),
+// сертификаты из реестра certified_<свойство> и revoked_<свойство> (см. Attestations),
+// операторы ! && || ( ), сравнения < <= > >= == != с числами и true/false.
+type RulesDictionary struct {
+	Version string                       `json:"version" jsonschema:"required,minLength=1"`
+	Rules   map[string]map[string]string `json:"rules" jsonschema:"required"`
+}
+
+//go:embed rules.json
+var builtinRules []byte
+
+var (
+	rulesMu sync.RWMutex
+	rules   = mustCompileRules(builtinRules)
+)
+
+// ruleHelpers - эвристики GiftItem, доступные в выражениях.
+var ruleHelpers = map[string]func(*GiftItem) bool{
+	"contains_pork":                (*GiftItem).ContainsPork,
+	"halal_by_slaughter":           (*GiftItem).IsSlaughteredAccordingToHalal,
+	"kosher_by_ingredients":        (*GiftItem).IsKosherByIngredients,
+	"allergenic_materials":         (*GiftItem).ContainsAllergenicMaterials,
+	"safety_certified":             (*GiftItem).HasSafetyCertification,
+	"contains_bpa":                 (*GiftItem).ContainsBPA,
+	"focus_enhancing":              (*GiftItem).IsFocusEnhancing,
+	"autism_friendly_design":       (*GiftItem).IsAutismFriendlyByDesign,
+	"wheelchair_accessible_design": (*GiftItem).IsWheelchairAccessibleByDesign,
+	"recycled_materials":           (*GiftItem).IsMadeFromRecycledMaterials,
+	"educational_category":         (*GiftItem).IsEducationalByCategory,
+	"gender_specific":              (*GiftItem).IsGenderSpecific,
+	"long_warranty":                (*GiftItem).HasLongWarranty,
+}
+
+// RuleHelpers возвращает названия эвристик, доступных в выражениях.
+func RuleHelpers() []string {
+	names := make([]string, 0, len(ruleHelpers))
+	for name := range ruleHelpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinRules возвращает встроенные правила.
+func BuiltinRules() RulesDictionary {
+	var d RulesDictionary
+	if err := json.Unmarshal(builtinRules, &d); err != nil {
+		panic(fmt.Sprintf("встроенные правила соответствия: %v", err))
+	}
+	return d
+}
+
+// Override возвращает правила, в которых требования из override заменяют текущие.
+func (d RulesDictionary) Override(override RulesDictionary) RulesDictionary {
+	result := RulesDictionary{Version: d.Version, Rules: make(map[string]map[string]string, len(d.Rules))}
+	for category, reqs := range d.Rules {
+		result.Rules[category] = make(map[string]string, len(reqs))
+		for req, expr := range reqs {
+			result.Rules[category][req] = expr
+		}
+	}
+
+	if override.Version != "" {
+		result.Version = d.Version + "+" + override.Version
+	}
+	for category, reqs := range override.Rules {
+		if result.Rules[category] == nil {
+			result.Rules[category] = make(map[string]string, len(reqs))
+		}
+		for req, expr := range reqs {
+			result.Rules[category][req] = expr
+		}
+	}
+	return result
+}
+
+// Validate проверяет, что все требования известны и все выражения разбираются.
+func (d RulesDictionary) Validate() error {
+	_, err := compileRules(d)
+	return err
+}
+
+// UseRules проверяет правила и делает их текущими для CompliesWith*.
+func UseRules(d RulesDictionary) error {
+	compiled, err := compileRules(d)
+	if err != nil {
+		return err
+	}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules = compiled
+	return nil
+}
+
+// RulesVersion возвращает версию текущих правил.
+func RulesVersion() string {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	return rules.version
+}
+
+// evaluateRule вычисляет правило требования. Неизвестное требование считается выполненным.
+func evaluateRule(category, requirement string, g *GiftItem) bool {
+	rulesMu.RLock()
+	rule, ok := rules.rules[category][requirement]
+	rulesMu.RUnlock()
+
+	if !ok {
+		return true
+	}
+	return rule(g).b
+}
+
+type compiledRules struct {
+	version string
+	rules   map[string]map[string]ruleFunc
+}
+
+func mustCompileRules(data []byte) compiledRules {
+	var d RulesDictionary
+	if err := json.Unmarshal(data, &d); err != nil {
+		panic(fmt.Sprintf("встроенные правила соответствия: %v", err))
+	}
+	c, err := compileRules(d)
+	if err != nil {
+		panic(fmt.Sprintf("встроенные правила соответствия: %v", err))
+	}
+	for category, values := range GetAllRequirements() {
+		for _, req := range values {
+			if _, ok := c.rules[category][req]; !ok {
+				panic(fmt.Sprintf("встроенные правила соответствия: нет правила %s.%s", category, req))
+			}
+		}
+	}
+	return c
+}
+
+func compileRules(d RulesDictionary) (compiledRules, error) {
+	known := GetAllRequirements()
+	c := compiledRules{version: d.Version, rules: make(map[string]map[string]ruleFunc, len(d.Rules))}
+
+	for category, reqs := range d.Rules {
+		values, ok := known[category]
+		if !ok {
+			return compiledRules{}, fmt.Errorf("неизвестная категория требований: %s", category)
+		}
+
+		c.rules[category] = make(map[string]ruleFunc, len(reqs))
+		for req, expr := range reqs {
+			if !slices.Contains(values, req) {
+				return compiledRules{}, fmt.Errorf("неизвестное требование %s.%s", category, req)
+			}
+
+			rule, err := compileRule(expr)
+			if err != nil {
+				return compiledRules{}, fmt.Errorf("правило %s.%s: %w", category, req, err)
+			}
+			c.rules[category][req] = rule
+		}
+	}
+
+	return c, nil
+}
+
+// ==================== Разбор выражений ====================
+
+// ruleValue - значение выражения: логическое или число.
+type ruleValue struct {
+	b   bool
+	num float64
+}
+
+type ruleFunc func(*GiftItem) ruleValue
+
+type ruleType int
+
+const (
+	ruleBool ruleType = iota
+	ruleNumber
+)
+
+// metadataFields - поля GiftMetadata, доступные в выражениях, по JSON-именам.
+var metadataFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(GiftMetadata{})
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if k := f.Type.Kind(); k != reflect.Bool && k != reflect.Float64 {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		fields[name] = i
+	}
+	return fields
+}()
+
+type ruleParser struct {
+	tokens []string
+	pos    int
+}
+
+// compileRule разбирает выражение и проверяет, что его результат логический.
+func compileRule(expr string) (ruleFunc, error) {
+	tokens, err := lexRule(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("пустое выражение")
+	}
+
+	p := &ruleParser{tokens: tokens}
+	fn, typ, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("лишний фрагмент %q", p.tokens[p.pos])
+	}
+	if typ != ruleBool {
+		return nil, fmt.Errorf("результат выражения должен быть логическим")
+	}
+	return fn, nil
+}
+
+func lexRule(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("!<>=&|", r):
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				if slices.Contains([]string{"&&", "||", "<=", ">=", "==", "!="}, two) {
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if r == '!' || r == '<' || r == '>' {
+				tokens = append(tokens, string(r))
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("неизвестный оператор в позиции %d", i+1)
+		case unicode.IsLetter(r) || r == '_' || unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("недопустимый символ %q в позиции %d", r, i+1)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *ruleParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *ruleParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *ruleParser) or() (ruleFunc, ruleType, error) {
+	left, typ, err := p.and()
+	if err != nil {
+		return nil, 0, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, rtyp, err := p.and()
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ != ruleBool || rtyp != ruleBool {
+			return nil, 0, fmt.Errorf("оператор || применим только к логическим значениям")
+		}
+		l, r := left, right
+		left = func(g *GiftItem) ruleValue { return ruleValue{b: l(g).b || r(g).b} }
+	}
+	return left, typ, nil
+}
+
+func (p *ruleParser) and() (ruleFunc, ruleType, error) {
+	left, typ, err := p.unary()
+	if err != nil {
+		return nil, 0, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, rtyp, err := p.unary()
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ != ruleBool || rtyp != ruleBool {
+			return nil, 0, fmt.Errorf("оператор && применим только к логическим значениям")
+		}
+		l, r := left, right
+		left = func(g *GiftItem) ruleValue { return ruleValue{b: l(g).b && r(g).b} }
+	}
+	return left, typ, nil
+}
+
+func (p *ruleParser) unary() (ruleFunc, ruleType, error) {
+	if p.peek() == "!" {
+		p.next()
+		operand, typ, err := p.unary()
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ != ruleBool {
+			return nil, 0, fmt.Errorf("оператор ! применим только к логическому значению")
+		}
+		return func(g *GiftItem) ruleValue { return ruleValue{b: !operand(g).b} }, ruleBool, nil
+	}
+	return p.comparison()
+}
+
+func (p *ruleParser) comparison() (ruleFunc, ruleType, error) {
+	left, ltyp, err := p.primary()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	op := p.peek()
+	if !slices.Contains([]string{"<", "<=", ">", ">=", "==", "!="}, op) {
+		return left, ltyp, nil
+	}
+	p.next()
+
+	right, rtyp, err := p.primary()
+	if err != nil {
+		return nil, 0, err
+	}
+	if ltyp != rtyp {
+		return nil, 0, fmt.Errorf("оператор %s: разные типы операндов", op)
+	}
+	if ltyp == ruleBool && op != "==" && op != "!=" {
+		return nil, 0, fmt.Errorf("оператор %s применим только к числам", op)
+	}
+
+	cmp := func(a, b ruleValue) bool {
+		switch op {
+		case "<":
+			return a.num < b.num
+		case "<=":
+			return a.num <= b.num
+		case ">":
+			return a.num > b.num
+		case ">=":
+			return a.num >= b.num
+		case "==":
+			return a == b
+		default:
+			return a != b
+		}
+	}
+	return func(g *GiftItem) ruleValue { return ruleValue{b: cmp(left(g), right(g))} }, ruleBool, nil
+}
+
+func (p *ruleParser) primary() (ruleFunc, ruleType, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, 0, fmt.Errorf("неожиданный конец выражения")
+	case t == "(":
+		fn, typ, err := p.or()
+		if err != nil {
+			return nil, 0, err
+		}
+		if p.next() != ")" {
+			return nil, 0, fmt.Errorf("не хватает закрывающей скобки")
+		}
+		return fn, typ, nil
+	case t == "true" || t == "false":
+		v := ruleValue{b: t == "true"}
+		return func(*GiftItem) ruleValue { return v }, ruleBool, nil
+	}
+
+	if n, err := strconv.ParseFloat(t, 64); err == nil {
+		v := ruleValue{num: n}
+		return func(*GiftItem) ruleValue { return v }, ruleNumber, nil
+	}
+
+	if helper, ok := ruleHelpers[t]; ok {
+		return func(g *GiftItem) ruleValue { return ruleValue{b: helper(g)} }, ruleBool, nil
+	}
+
+	if a, ok := strings.CutPrefix(t, "certified_"); ok && slices.Contains(Attestations, a) {
+		return func(g *GiftItem) ruleValue {
+			valid, _ := g.Attested(a)
+			return ruleValue{b: valid}
+		}, ruleBool, nil
+	}
+
+	if a, ok := strings.CutPrefix(t, "revoked_"); ok && slices.Contains(Attestations, a) {
+		return func(g *GiftItem) ruleValue {
+			_, revoked := g.Attested(a)
+			return ruleValue{b: revoked}
+		}, ruleBool, nil
+	}
+
+	if idx, ok := metadataFields[t]; ok {
+		if reflect.TypeOf(GiftMetadata{}).Field(idx).Type.Kind() == reflect.Float64 {
+			return func(g *GiftItem) ruleValue {
+				return ruleValue{num: reflect.ValueOf(g.Metadata).Field(idx).Float()}
+			}, ruleNumber, nil
+		}
+		return func(g *GiftItem) ruleValue {
+			return ruleValue{b: reflect.ValueOf(g.Metadata).Field(idx).Bool()}
+		}, ruleBool, nil
+	}
+
+	return nil, 0, fmt.Errorf("неизвестное имя %q (эвристики: %s; поля метаданных и certified_/revoked_<свойство> для сертификатов)",
+		t, strings.Join(RuleHelpers(), ", "))
+}
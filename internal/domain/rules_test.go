@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// TestRulesGolden проверяет, что встроенные правила воспроизводят
+// прежнюю логику CompliesWith* на каталоге из testdata.
+func TestRulesGolden(t *testing.T) {
+	data, err := os.ReadFile("testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var catalog CatalogData
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = os.ReadFile("testdata/compliance.golden.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden map[string]map[string]bool
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatal(err)
+	}
+
+	all := GetAllRequirements()
+	reqs := &SpecialRequirements{}
+	for _, v := range all["dietary"] {
+		reqs.Dietary = append(reqs.Dietary, DietaryRequirement(v))
+	}
+	for _, v := range all["safety"] {
+		reqs.Safety = append(reqs.Safety, SafetyRequirement(v))
+	}
+	for _, v := range all["medical"] {
+		reqs.Medical = append(reqs.Medical, MedicalRequirement(v))
+	}
+	for _, v := range all["other"] {
+		reqs.Other = append(reqs.Other, OtherRequirement(v))
+	}
+
+	if len(golden) != len(catalog.Items) {
+		t.Fatalf("в эталоне %d предметов, в каталоге %d", len(golden), len(catalog.Items))
+	}
+
+	for _, item := range catalog.Items {
+		id := strconv.Itoa(item.Id)
+		want, ok := golden[id]
+		if !ok {
+			t.Errorf("предмет %s отсутствует в эталоне", id)
+			continue
+		}
+
+		gift := item.ToGiftItem()
+		got := gift.GetComplianceSummary(reqs)
+		if len(got) != len(want) {
+			t.Errorf("предмет %s: требований %d, ожидалось %d", id, len(got), len(want))
+		}
+		for key, w := range want {
+			if got[key] != w {
+				t.Errorf("предмет %s, %s: получено %v, ожидалось %v", id, key, got[key], w)
+			}
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		expr string
+		ok   bool
+	}{
+		{"пример из описания", "!contains_meat && !contains_fish && !contains_dairy || vegan", true},
+		{"сравнение", "!has_small_parts || small_parts_size >= 3", true},
+		{"скобки", "(tactile || predictable) && !has_flashing_lights", true},
+		{"неизвестное имя", "!contains_meet", false},
+		{"числовой результат", "small_parts_size", false},
+		{"отрицание числа", "!small_parts_size", false},
+		{"незакрытая скобка", "(vegan || vegetarian", false},
+		{"одиночный амперсанд", "vegan & vegetarian", false},
+		{"пустое выражение", "  ", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := compileRule(tc.expr)
+			if (err == nil) != tc.ok {
+				t.Errorf("compileRule(%q): ошибка %v", tc.expr, err)
+			}
+		})
+	}
+
+	unknown := RulesDictionary{Version: "1", Rules: map[string]map[string]string{"dietary": {"paleo": "true"}}}
+	if err := unknown.Validate(); err == nil {
+		t.Error("неизвестное требование должно отклоняться")
+	}
+}
+
+// TestRulesCertifications проверяет, что сертификат из реестра важнее флагов:
+// действующий подтверждает свойство, просроченный отменяет флаг.
+func TestRulesCertifications(t *testing.T) {
+	UseCertifications(CertificationRegistry{
+		Version: "test",
+		Certificates: []Certificate{
+			{ID: "halal", Names: []string{"халяль"}, Attests: []string{"halal"}, Expires: "2030-12-31"},
+			{ID: "kosher", Names: []string{"кошер"}, Attests: []string{"kosher"}, Expires: "2020-12-31"},
+			{ID: "en71", Names: []string{"EN71"}, Attests: []string{"safety", "en71"}, Expires: "2020-12-31"},
+		},
+	})
+	defer UseCertifications(CertificationRegistry{})
+
+	valid := GiftItem{Name: "Пряник", Metadata: GiftMetadata{Certifications: []string{"Халяль"}, Materials: []string{"свинина"}}}
+	if !valid.CompliesWithDietary(DietaryHalal) {
+		t.Error("действующий сертификат халяль должен подтверждать требование")
+	}
+
+	expired := GiftItem{Name: "Пряник", Metadata: GiftMetadata{Certifications: []string{"кошер"}, KosherCertified: true}}
+	if expired.CompliesWithDietary(DietaryKosher) {
+		t.Error("просроченный сертификат должен отменять флаг kosher_certified")
+	}
+
+	for _, certs := range [][]string{{"EN71"}, {"ГОСТ 25779"}} {
+		item := GiftItem{Name: "Кубики", Metadata: GiftMetadata{Certifications: certs}}
+		if item.CompliesWithSafety(SafetyNonToxic) {
+			t.Errorf("просроченный или неизвестный сертификат %v не должен подтверждать non_toxic", certs)
+		}
+	}
+}
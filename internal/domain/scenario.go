@@ -0,0 +1,16 @@
+package domain
+
+// CoefficientTable - именованный вариант региональных коэффициентов.
+type CoefficientTable struct {
+	Name    string   `json:"name" jsonschema:"required,minLength=1"`
+	Regions []Region `json:"regions"`
+}
+
+// ScenarioGrid описывает сетку сценариев "что если": расчет выполняется
+// для каждого сочетания бюджета, количества позиций и таблицы коэффициентов.
+// Пустой список означает текущее значение из настроек.
+type ScenarioGrid struct {
+	MaxBudgets   []Money            `json:"max_budgets,omitempty"`
+	MaxCounts    []int              `json:"max_counts,omitempty"`
+	Coefficients []CoefficientTable `json:"coefficients,omitempty"`
+}
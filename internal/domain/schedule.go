@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeZone - часовой пояс регионов, для которых пояс не задан.
+const DefaultTimeZone = "Europe/Moscow"
+
+// ScheduleRules - параметры расписания доставки.
+type ScheduleRules struct {
+	// Start - выезд первого рейса со склада.
+	Start time.Time `json:"start"`
+
+	// Vehicles - число саней или грузовиков, работающих одновременно.
+	Vehicles int `json:"vehicles,omitempty" jsonschema:"minimum=0"`
+
+	// Speed - средняя скорость в пути, км/ч.
+	Speed float64 `json:"speed,omitempty" jsonschema:"minimum=0"`
+
+	// LoadingMinutes - время погрузки рейса на складе, мин.
+	LoadingMinutes int `json:"loading_minutes,omitempty" jsonschema:"minimum=0"`
+
+	// DefaultTravelHours - время в пути, если координаты склада или региона не заданы, ч.
+	DefaultTravelHours float64 `json:"default_travel_hours,omitempty" jsonschema:"minimum=0"`
+
+	// TimeZone - часовой пояс регионов без собственного пояса, по умолчанию Europe/Moscow.
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// DefaultScheduleRules - параметры расписания, если они не заданы в конфигурации.
+var DefaultScheduleRules = ScheduleRules{
+	Vehicles:           1,
+	Speed:              800,
+	LoadingMinutes:     30,
+	DefaultTravelHours: 6,
+	TimeZone:           DefaultTimeZone,
+}
+
+// WithDefaults дополняет незаданные параметры значениями DefaultScheduleRules.
+func (r ScheduleRules) WithDefaults() ScheduleRules {
+	if r.Vehicles == 0 {
+		r.Vehicles = DefaultScheduleRules.Vehicles
+	}
+	if r.Speed == 0 {
+		r.Speed = DefaultScheduleRules.Speed
+	}
+	if r.LoadingMinutes == 0 {
+		r.LoadingMinutes = DefaultScheduleRules.LoadingMinutes
+	}
+	if r.DefaultTravelHours == 0 {
+		r.DefaultTravelHours = DefaultScheduleRules.DefaultTravelHours
+	}
+	if r.TimeZone == "" {
+		r.TimeZone = DefaultScheduleRules.TimeZone
+	}
+	return r
+}
+
+// Validate проверяет параметры расписания.
+func (r *ScheduleRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Vehicles < 0 || r.Speed < 0 || r.LoadingMinutes < 0 || r.DefaultTravelHours < 0 {
+		return fmt.Errorf("параметры расписания не могут быть отрицательными")
+	}
+	if r.TimeZone != "" {
+		if _, err := time.LoadLocation(r.TimeZone); err != nil {
+			return fmt.Errorf("неизвестный часовой пояс %q", r.TimeZone)
+		}
+	}
+	return nil
+}
+
+// TravelTime возвращает время в пути между точками. Если координаты не заданы
+// или скорость не указана, используется DefaultTravelHours.
+func (r ScheduleRules) TravelTime(from, to *Coordinates) time.Duration {
+	hours := r.DefaultTravelHours
+	if from != nil && to != nil && r.Speed > 0 {
+		hours = from.DistanceTo(*to) / r.Speed
+	}
+	return time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+}
+
+// ScheduledLoad - рейс в расписании: окно выезда, прибытие и запас до полуночи.
+type ScheduledLoad struct {
+	Number  int    `json:"number"`
+	Region  string `json:"region"`
+	Vehicle int    `json:"vehicle"`
+
+	// DepartureFrom - выезд по расписанию, DepartureBy - крайний срок выезда,
+	// при котором рейс успевает к полуночи.
+	DepartureFrom time.Time `json:"departure_from"`
+	DepartureBy   time.Time `json:"departure_by"`
+	Arrival       time.Time `json:"arrival"`
+	Deadline      time.Time `json:"deadline"`
+	Late          bool      `json:"late,omitempty"`
+}
+
+// RegionSchedule - сроки доставки в регион.
+type RegionSchedule struct {
+	Region      string    `json:"region"`
+	TimeZone    string    `json:"time_zone"`
+	Deadline    time.Time `json:"deadline"`
+	LastArrival time.Time `json:"last_arrival"`
+	Loads       int       `json:"loads"`
+	Late        bool      `json:"late,omitempty"`
+}
+
+// DeliverySchedule - расписание доставки: регионы в порядке наступления
+// Нового года и рейсы с окнами выезда.
+type DeliverySchedule struct {
+	Start   time.Time        `json:"start"`
+	Regions []RegionSchedule `json:"regions"`
+	Loads   []ScheduledLoad  `json:"loads"`
+}
+
+// LateRegions возвращает регионы, куда подарки не успевают к полуночи.
+func (s DeliverySchedule) LateRegions() []string {
+	var result []string
+	for _, r := range s.Regions {
+		if r.Late {
+			result = append(result, r.Region)
+		}
+	}
+	return result
+}
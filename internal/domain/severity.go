@@ -0,0 +1,94 @@
+package domain
+
+import "fmt"
+
+// Severity - строгость категории требований.
+type Severity string
+
+const (
+	// SeverityHard - предмет, не соответствующий требованию, исключается.
+	SeverityHard Severity = "hard"
+	// SeveritySoft - требование является предпочтением и влияет только на порядок кандидатов.
+	SeveritySoft Severity = "soft"
+)
+
+// protectedCategories - категории, которые всегда остаются жесткими:
+// здоровье и безопасность ребенка не обмениваются на предпочтения.
+var protectedCategories = map[string]bool{
+	"dietary": true,
+	"safety":  true,
+	"medical": true,
+}
+
+// RequirementSeverity задает строгость по категориям требований
+// ("dietary", "safety", "medical", "other"). Не указанные категории
+// получают строгость по умолчанию: прочие требования мягкие, остальные жесткие.
+type RequirementSeverity map[string]Severity
+
+// Validate проверяет категории и значения строгости.
+func (r RequirementSeverity) Validate() error {
+	known := GetAllRequirements()
+	for category, severity := range r {
+		if _, ok := known[category]; !ok {
+			return fmt.Errorf("неизвестная категория требований: %s", category)
+		}
+		switch severity {
+		case SeverityHard:
+		case SeveritySoft:
+			if protectedCategories[category] {
+				return fmt.Errorf("категория %s не может быть мягкой", category)
+			}
+		default:
+			return fmt.Errorf("неизвестная строгость %q для категории %s (допустимо: %s, %s)",
+				severity, category, SeverityHard, SeveritySoft)
+		}
+	}
+	return nil
+}
+
+// Of возвращает строгость категории требований.
+func (r RequirementSeverity) Of(category string) Severity {
+	if protectedCategories[category] {
+		return SeverityHard
+	}
+	if severity, ok := r[category]; ok {
+		return severity
+	}
+	if category == "other" {
+		return SeveritySoft
+	}
+	return SeverityHard
+}
+
+// Split делит требования ребенка на жесткие ограничения и мягкие предпочтения.
+// Для nil требований обе части nil.
+func (r RequirementSeverity) Split(reqs *SpecialRequirements) (hard, soft *SpecialRequirements) {
+	if reqs == nil {
+		return nil, nil
+	}
+
+	hard, soft = &SpecialRequirements{}, &SpecialRequirements{}
+	pick := func(category string) *SpecialRequirements {
+		if r.Of(category) == SeveritySoft {
+			return soft
+		}
+		return hard
+	}
+
+	pick("dietary").Dietary = reqs.Dietary
+	pick("safety").Safety = reqs.Safety
+	pick("medical").Medical = reqs.Medical
+	pick("other").Other = reqs.Other
+	return hard, soft
+}
+
+// PreferenceScore возвращает число выполненных предпочтений и их общее число.
+func (g *GiftItem) PreferenceScore(soft *SpecialRequirements) (met, total int) {
+	for _, ok := range g.GetComplianceSummary(soft) {
+		total++
+		if ok {
+			met++
+		}
+	}
+	return met, total
+}
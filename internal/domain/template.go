@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TemplateSlot описывает одну позицию стандартного набора.
+type TemplateSlot struct {
+	// Name - название слота, например "сладость" или "книга".
+	Name string `json:"name" jsonschema:"required,minLength=1"`
+
+	// Category - категория каталога, из которой заполняется слот.
+	Category string `json:"category" jsonschema:"required,minLength=1"`
+
+	// MinPrice и MaxPrice ограничивают цену предмета в слоте, 0 - без ограничения.
+	MinPrice Money `json:"min_price,omitempty" jsonschema:"minimum=0"`
+	MaxPrice Money `json:"max_price,omitempty" jsonschema:"minimum=0"`
+
+	// DefaultItemID - предмет по умолчанию; используется, если он подходит ребенку.
+	DefaultItemID int `json:"default_item_id,omitempty"`
+
+	// Optional - слот может остаться пустым без предупреждения.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// GiftTemplate - стандартный набор подарка для возрастных групп.
+type GiftTemplate struct {
+	Name      string         `json:"name" jsonschema:"required,minLength=1"`
+	AgeGroups []string       `json:"age_groups" jsonschema:"required"`
+	Slots     []TemplateSlot `json:"slots" jsonschema:"required"`
+}
+
+// Accepts проверяет, предназначен ли набор для ребенка.
+func (t *GiftTemplate) Accepts(child *Child) bool {
+	return slices.Contains(t.AgeGroups, child.AgeGroup())
+}
+
+// Allows проверяет, подходит ли цена предмета под ограничения слота.
+func (s *TemplateSlot) Allows(price Money) bool {
+	if s.MinPrice > 0 && price < s.MinPrice {
+		return false
+	}
+	if s.MaxPrice > 0 && price > s.MaxPrice {
+		return false
+	}
+	return true
+}
+
+// Fits проверяет, подходит ли предмет слоту по категории и цене.
+func (s *TemplateSlot) Fits(item CatalogItem) bool {
+	return item.Category == s.Category && s.Allows(item.Price)
+}
+
+// ValidateTemplates проверяет корректность набора шаблонов.
+// Предметы по умолчанию проверяются по каталогу отдельно (см. ValidateTemplateDefaults).
+func ValidateTemplates(templates []GiftTemplate) error {
+	groups := AllAgeGroups()
+	names := make(map[string]bool)
+
+	for _, t := range templates {
+		if strings.TrimSpace(t.Name) == "" {
+			return fmt.Errorf("название шаблона не может быть пустым")
+		}
+		if names[t.Name] {
+			return fmt.Errorf("шаблон %s описан повторно", t.Name)
+		}
+		names[t.Name] = true
+
+		if len(t.Slots) == 0 {
+			return fmt.Errorf("шаблон %s не содержит слотов", t.Name)
+		}
+		for _, group := range t.AgeGroups {
+			if !slices.Contains(groups, group) {
+				return fmt.Errorf("шаблон %s: неизвестная возрастная группа %s", t.Name, group)
+			}
+		}
+		for _, slot := range t.Slots {
+			if slot.MaxPrice > 0 && slot.MinPrice > slot.MaxPrice {
+				return fmt.Errorf("шаблон %s, слот %s: min_price больше max_price", t.Name, slot.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateTemplateDefaults проверяет, что предметы слотов по умолчанию есть
+// в каталоге и подходят слотам по категории и цене.
+func ValidateTemplateDefaults(templates []GiftTemplate, catalog []CatalogItem) error {
+	byID := make(map[int]CatalogItem, len(catalog))
+	for _, item := range catalog {
+		byID[item.Id] = item
+	}
+
+	for _, t := range templates {
+		for _, slot := range t.Slots {
+			if slot.DefaultItemID == 0 {
+				continue
+			}
+			item, ok := byID[slot.DefaultItemID]
+			if !ok {
+				return fmt.Errorf("шаблон %s, слот %s: предмет по умолчанию %d не найден в каталоге",
+					t.Name, slot.Name, slot.DefaultItemID)
+			}
+			if item.Category != slot.Category {
+				return fmt.Errorf("шаблон %s, слот %s: предмет по умолчанию %d из категории %s, а слот - %s",
+					t.Name, slot.Name, item.Id, item.Category, slot.Category)
+			}
+			if !slot.Allows(item.Price) {
+				return fmt.Errorf("шаблон %s, слот %s: цена предмета по умолчанию %d (%s) вне диапазона слота",
+					t.Name, slot.Name, item.Id, item.Price)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindTemplate возвращает первый шаблон, подходящий ребенку по возрастной группе.
+func FindTemplate(templates []GiftTemplate, child *Child) *GiftTemplate {
+	for i := range templates {
+		if templates[i].Accepts(child) {
+			return &templates[i]
+		}
+	}
+	return nil
+}
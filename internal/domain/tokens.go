@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minStemLength - основа слова не укорачивается короче этого числа букв.
+const minStemLength = 3
+
+// stemSuffixes - русские окончания, отбрасываемые при выделении основы, от длинных к коротким.
+// Набор намеренно простой: он нужен для сопоставления ключевых слов, а не для морфологии.
+var stemSuffixes = []string{
+	"ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "ешь", "ете", "ают", "ят", "ут", "ют",
+	"ая", "яя", "ое", "ее", "ие", "ые", "ой", "ей", "ий", "ый", "ом", "ем", "ам", "ям", "ах", "ях",
+	"ов", "ев", "ию", "ью", "ия", "ья", "ии", "ую", "юю",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+}
+
+// englishSuffix - английское окончание и условия, при которых оно отбрасывается.
+type englishSuffix struct {
+	suffix string
+	// minStem - минимальная длина основы после отбрасывания окончания.
+	minStem int
+	// sibilant - окончание отбрасывается только после s, x, z, ch, sh (boxes, but not cares).
+	sibilant bool
+}
+
+// englishSuffixes - английские окончания от длинных к коротким. Для ed и ing
+// основа должна быть длиннее, чтобы cared и caring не сводились к car.
+var englishSuffixes = []englishSuffix{
+	{suffix: "ies", minStem: minStemLength},
+	{suffix: "ing", minStem: minStemLength + 1},
+	{suffix: "ed", minStem: minStemLength + 1},
+	{suffix: "es", minStem: minStemLength, sibilant: true},
+	{suffix: "ic", minStem: minStemLength},
+	{suffix: "y", minStem: minStemLength},
+	{suffix: "s", minStem: minStemLength},
+}
+
+// combiningReplacer собирает "й" и "ё", записанные буквой с комбинируемым
+// знаком, в одну букву, чтобы они совпадали с составными формами.
+var combiningReplacer = strings.NewReplacer("и\u0306", "й", "е\u0308", "ё")
+
+// Tokenize разбивает текст на слова в нижнем регистре.
+// Разделителем считается любой символ, кроме букв, цифр и комбинируемых знаков;
+// "ё" заменяется на "е", остальные комбинируемые знаки (например, ударения) отбрасываются.
+func Tokenize(text string) []string {
+	text = combiningReplacer.Replace(strings.ToLower(text))
+	text = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		if r == 'ё' {
+			return 'е'
+		}
+		return r
+	}, text)
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stem возвращает основу слова, отбрасывая одно окончание.
+func Stem(word string) string {
+	if isLatin(word) {
+		return stemEnglish(word)
+	}
+	for _, suffix := range stemSuffixes {
+		if !strings.HasSuffix(word, suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(word, suffix)
+		if utf8.RuneCountInString(stem) >= minStemLength {
+			return stem
+		}
+	}
+	return word
+}
+
+func stemEnglish(word string) string {
+	for _, rule := range englishSuffixes {
+		stem, ok := strings.CutSuffix(word, rule.suffix)
+		if !ok {
+			continue
+		}
+		if len(stem) < rule.minStem {
+			continue
+		}
+		if rule.sibilant && !hasSibilantEnding(stem) {
+			continue
+		}
+		return stem
+	}
+	return word
+}
+
+func hasSibilantEnding(stem string) bool {
+	for _, end := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(stem, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLatin сообщает, что слово записано латиницей.
+func isLatin(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stems разбивает текст на слова и возвращает их основы.
+func Stems(text string) []string {
+	tokens := Tokenize(text)
+	for i, t := range tokens {
+		tokens[i] = Stem(t)
+	}
+	return tokens
+}
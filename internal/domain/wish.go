@@ -9,8 +9,8 @@ const (
 )
 
 type Wish struct {
-	ChildID  int          `json:"child_id"`
-	ItemIDs  []int        `json:"item_ids"`
+	ChildID  int          `json:"child_id" jsonschema:"required,minimum=1"`
+	ItemIDs  []int        `json:"item_ids" jsonschema:"required"`
 	Priority WishPriority `json:"priority"`
 }
 
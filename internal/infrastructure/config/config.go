@@ -0,0 +1,482 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"giftcalc/internal/domain"
+)
+
+// FileName - имя файла конфигурации, который ищется в каталоге данных.
+const FileName = "giftcalc.json"
+
+// EnvPrefix - префикс переменных окружения, переопределяющих настройки.
+const EnvPrefix = "GIFTCALC_"
+
+// Settings содержит параметры расчета.
+// Нулевые значения означают "не задано" и не перекрывают предыдущий источник.
+type Settings struct {
+	Children    string          `json:"children,omitempty"`
+	Catalog     string          `json:"catalog,omitempty"`
+	Wishes      string          `json:"wishes,omitempty"`
+	Report      string          `json:"report,omitempty"`
+	MaxBudget   domain.Money    `json:"max_budget,omitempty"`
+	MaxCount    int             `json:"max_count,omitempty"`
+	MaxWeight   float64         `json:"max_weight,omitempty"`
+	Strategy    string          `json:"strategy,omitempty"`
+	Allocation  string          `json:"allocation,omitempty"`
+	Mode        string          `json:"mode,omitempty"`
+	RegionsFile string          `json:"regions_file,omitempty"`
+	Regions     []domain.Region `json:"regions,omitempty"`
+
+	// Composition - правила состава подарка.
+	Composition *domain.CompositionRules `json:"composition,omitempty"`
+
+	// Templates - стандартные наборы подарков для режима template.
+	Templates []domain.GiftTemplate `json:"templates,omitempty"`
+
+	// Household - правила подбора для детей одной семьи.
+	Household *domain.HouseholdRules `json:"household,omitempty"`
+
+	// HistoryFile - файл истории подарков прошлых лет.
+	HistoryFile string `json:"history_file,omitempty"`
+
+	// History - правила учета повторов; nil - история не учитывается.
+	History *domain.HistoryRules `json:"history,omitempty"`
+
+	// NotesDictionary - словарь для разбора заметок о детях.
+	NotesDictionary string `json:"notes_dictionary,omitempty"`
+
+	// Keywords - словарь ключевых слов, дополняющий встроенный; файл может отсутствовать.
+	Keywords string `json:"keywords,omitempty"`
+
+	// Severity - строгость категорий требований; по умолчанию прочие требования мягкие.
+	Severity domain.RequirementSeverity `json:"severity,omitempty"`
+
+	// SafetyPolicy - дополнительные возрастные правила безопасности; обязательные
+	// правила (без мелких деталей до 3 лет) действуют всегда.
+	SafetyPolicy *domain.SafetyPolicy `json:"safety_policy,omitempty"`
+
+	// Rules - правила соответствия требованиям, заменяющие встроенные; файл может отсутствовать.
+	Rules string `json:"rules,omitempty"`
+
+	// Certifications - реестр сертификатов; файл может отсутствовать.
+	Certifications string `json:"certifications,omitempty"`
+
+	// BaseCurrency - валюта кампании (ISO 4217), в ней проверяются бюджеты.
+	BaseCurrency string `json:"base_currency,omitempty"`
+
+	// ExchangeRates - таблица курсов валют; файл может отсутствовать,
+	// если все цены каталога в базовой валюте.
+	ExchangeRates string `json:"exchange_rates,omitempty"`
+
+	// Delivery - модель стоимости доставки; тарифы регионов задаются в Regions.
+	Delivery *domain.DeliveryRules `json:"delivery,omitempty"`
+
+	// IncludeDelivery - включать доставку в проверку бюджета подарка.
+	IncludeDelivery *bool `json:"include_delivery,omitempty"`
+
+	// Logistics - вместимость рейсов и параметры коробок для плана загрузки.
+	Logistics *domain.LogisticsRules `json:"logistics,omitempty"`
+
+	// Schedule - параметры расписания доставки: выезд, скорость, число саней.
+	Schedule *domain.ScheduleRules `json:"schedule,omitempty"`
+
+	// Boxes - стандартные размеры коробок для подарков.
+	Boxes []domain.BoxSize `json:"boxes,omitempty"`
+}
+
+// File представляет структуру файла конфигурации giftcalc.json.
+type File struct {
+	// Profile - профиль по умолчанию, если не указан явно.
+	Profile string `json:"profile,omitempty"`
+
+	// Defaults - базовые настройки, общие для всех профилей.
+	Defaults Settings `json:"defaults"`
+
+	// Profiles - именованные профили, например "test" или "2025-final".
+	Profiles map[string]Settings `json:"profiles,omitempty"`
+
+	// dir - каталог файла, относительно него разрешаются пути.
+	dir string
+}
+
+// Defaults возвращает встроенные настройки для указанного каталога данных.
+func Defaults(dataDir string) Settings {
+	return Settings{
+		Children:        filepath.Join(dataDir, "children.json"),
+		Catalog:         filepath.Join(dataDir, "catalog.json"),
+		RegionsFile:     filepath.Join(dataDir, "regions.json"),
+		Report:          "report.json",
+		HistoryFile:     filepath.Join(dataDir, "history.json"),
+		NotesDictionary: filepath.Join(dataDir, "notes-dictionary.json"),
+		Keywords:        filepath.Join(dataDir, "keywords.json"),
+		Rules:           filepath.Join(dataDir, "rules.json"),
+		Certifications:  filepath.Join(dataDir, "certifications.json"),
+		ExchangeRates:   filepath.Join(dataDir, "exchange-rates.json"),
+		BaseCurrency:    domain.DefaultCurrency,
+		MaxBudget:       1000 * domain.MinorUnits,
+		MaxCount:        10,
+		Strategy:        domain.StrategyCatalogOrder,
+		Allocation:      domain.AllocationUnlimited,
+		Mode:            domain.ModeIndividual,
+		Boxes:           domain.StandardBoxes,
+	}
+}
+
+// Discover возвращает путь к файлу конфигурации.
+// Явно указанный путь должен существовать; иначе файл ищется в каталоге данных.
+// Возвращает пустую строку если файл не найден.
+func Discover(explicit, dataDir string) (string, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("файл конфигурации '%s' не найден: %w", explicit, err)
+		}
+		return explicit, nil
+	}
+
+	candidate := filepath.Join(dataDir, FileName)
+	if _, err := os.Stat(candidate); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return candidate, nil
+}
+
+// Load читает файл конфигурации.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не могу прочитать файл конфигурации '%s': %w", path, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+
+	f := &File{}
+	if err := dec.Decode(f); err != nil {
+		return nil, fmt.Errorf("не могу разобрать файл конфигурации '%s': %w", path, err)
+	}
+	f.dir = filepath.Dir(path)
+
+	if err := f.Defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+	for name, p := range f.Profiles {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("профиль %s: %w", name, err)
+		}
+	}
+
+	return f, nil
+}
+
+// ProfileNames возвращает отсортированный список профилей.
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve возвращает настройки файла с примененным профилем.
+// Пустое имя означает профиль по умолчанию из файла (если он указан).
+func (f *File) Resolve(profile string) (Settings, error) {
+	result := f.Defaults.resolvePaths(f.dir)
+
+	if profile == "" {
+		profile = f.Profile
+	}
+	if profile == "" {
+		return result, nil
+	}
+
+	p, ok := f.Profiles[profile]
+	if !ok {
+		return Settings{}, fmt.Errorf("профиль %q не найден (доступны: %s)",
+			profile, strings.Join(f.ProfileNames(), ", "))
+	}
+
+	return result.Merge(p.resolvePaths(f.dir)), nil
+}
+
+// Merge возвращает копию настроек, в которой заданные поля override перекрывают текущие.
+func (s Settings) Merge(override Settings) Settings {
+	if override.Children != "" {
+		s.Children = override.Children
+	}
+	if override.Catalog != "" {
+		s.Catalog = override.Catalog
+	}
+	if override.Wishes != "" {
+		s.Wishes = override.Wishes
+	}
+	if override.Report != "" {
+		s.Report = override.Report
+	}
+	if override.MaxBudget != 0 {
+		s.MaxBudget = override.MaxBudget
+	}
+	if override.MaxCount != 0 {
+		s.MaxCount = override.MaxCount
+	}
+	if override.MaxWeight != 0 {
+		s.MaxWeight = override.MaxWeight
+	}
+	if override.Strategy != "" {
+		s.Strategy = override.Strategy
+	}
+	if override.Allocation != "" {
+		s.Allocation = override.Allocation
+	}
+	if override.Mode != "" {
+		s.Mode = override.Mode
+	}
+	if override.RegionsFile != "" {
+		s.RegionsFile = override.RegionsFile
+	}
+	if len(override.Regions) > 0 {
+		s.Regions = override.Regions
+	}
+	if override.Composition != nil {
+		s.Composition = override.Composition
+	}
+	if len(override.Templates) > 0 {
+		s.Templates = override.Templates
+	}
+	if override.Household != nil {
+		s.Household = override.Household
+	}
+	if override.HistoryFile != "" {
+		s.HistoryFile = override.HistoryFile
+	}
+	if override.History != nil {
+		s.History = override.History
+	}
+	if override.NotesDictionary != "" {
+		s.NotesDictionary = override.NotesDictionary
+	}
+	if override.Keywords != "" {
+		s.Keywords = override.Keywords
+	}
+	if override.Severity != nil {
+		s.Severity = override.Severity
+	}
+	if override.SafetyPolicy != nil {
+		s.SafetyPolicy = override.SafetyPolicy
+	}
+	if override.Rules != "" {
+		s.Rules = override.Rules
+	}
+	if override.Certifications != "" {
+		s.Certifications = override.Certifications
+	}
+	if override.BaseCurrency != "" {
+		s.BaseCurrency = override.BaseCurrency
+	}
+	if override.ExchangeRates != "" {
+		s.ExchangeRates = override.ExchangeRates
+	}
+	if override.Delivery != nil {
+		s.Delivery = override.Delivery
+	}
+	if override.IncludeDelivery != nil {
+		s.IncludeDelivery = override.IncludeDelivery
+	}
+	if override.Logistics != nil {
+		s.Logistics = override.Logistics
+	}
+	if override.Schedule != nil {
+		s.Schedule = override.Schedule
+	}
+	if len(override.Boxes) > 0 {
+		s.Boxes = override.Boxes
+	}
+	return s
+}
+
+// FromEnv читает настройки из переменных окружения GIFTCALC_*.
+func FromEnv(lookup func(string) (string, bool)) (Settings, error) {
+	var s Settings
+
+	str := func(name string, dst *string) {
+		if v, ok := lookup(EnvPrefix + name); ok && v != "" {
+			*dst = v
+		}
+	}
+	str("CHILDREN", &s.Children)
+	str("CATALOG", &s.Catalog)
+	str("WISHES", &s.Wishes)
+	str("REPORT", &s.Report)
+	str("STRATEGY", &s.Strategy)
+	str("ALLOCATION", &s.Allocation)
+	str("MODE", &s.Mode)
+	str("REGIONS_FILE", &s.RegionsFile)
+	str("HISTORY_FILE", &s.HistoryFile)
+	str("NOTES_DICTIONARY", &s.NotesDictionary)
+	str("KEYWORDS", &s.Keywords)
+	str("RULES", &s.Rules)
+	str("CERTIFICATIONS", &s.Certifications)
+	str("BASE_CURRENCY", &s.BaseCurrency)
+	str("EXCHANGE_RATES", &s.ExchangeRates)
+
+	if v, ok := lookup(EnvPrefix + "MAX_BUDGET"); ok && v != "" {
+		n, err := domain.ParseMoney(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("%sMAX_BUDGET: %w", EnvPrefix, err)
+		}
+		s.MaxBudget = n
+	}
+	if v, ok := lookup(EnvPrefix + "MAX_WEIGHT"); ok && v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Settings{}, fmt.Errorf("%sMAX_WEIGHT: %w", EnvPrefix, err)
+		}
+		s.MaxWeight = n
+	}
+	if v, ok := lookup(EnvPrefix + "INCLUDE_DELIVERY"); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("%sINCLUDE_DELIVERY: %w", EnvPrefix, err)
+		}
+		s.IncludeDelivery = &b
+	}
+	if v, ok := lookup(EnvPrefix + "MAX_COUNT"); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("%sMAX_COUNT: %w", EnvPrefix, err)
+		}
+		s.MaxCount = n
+	}
+
+	return s, s.Validate()
+}
+
+// Validate проверяет корректность заданных значений.
+func (s Settings) Validate() error {
+	if s.MaxBudget < 0 {
+		return fmt.Errorf("бюджет не может быть отрицательным: %s", s.MaxBudget)
+	}
+	if s.MaxWeight < 0 {
+		return fmt.Errorf("вес не может быть отрицательным: %.2f", s.MaxWeight)
+	}
+	if s.MaxCount < 0 {
+		return fmt.Errorf("количество позиций не может быть отрицательным: %d", s.MaxCount)
+	}
+	switch s.Strategy {
+	case "", domain.StrategyCatalogOrder, domain.StrategyCheapestFirst:
+	default:
+		return fmt.Errorf("неизвестная стратегия: %s (допустимо: %s, %s)",
+			s.Strategy, domain.StrategyCatalogOrder, domain.StrategyCheapestFirst)
+	}
+	switch s.Allocation {
+	case "", domain.AllocationUnlimited, domain.AllocationStock:
+	default:
+		return fmt.Errorf("неизвестный режим распределения: %s (допустимо: %s, %s)",
+			s.Allocation, domain.AllocationUnlimited, domain.AllocationStock)
+	}
+	switch s.Mode {
+	case "", domain.ModeIndividual, domain.ModeTemplate:
+	default:
+		return fmt.Errorf("неизвестный режим подбора: %s (допустимо: %s, %s)",
+			s.Mode, domain.ModeIndividual, domain.ModeTemplate)
+	}
+	for _, r := range s.Regions {
+		if r.Coefficient <= 0 {
+			return fmt.Errorf("коэффициент региона %s должен быть положительным", r.Name)
+		}
+		if _, err := r.Zone(time.UTC); err != nil {
+			return err
+		}
+		if r.Delivery != nil {
+			if err := r.Delivery.Validate(); err != nil {
+				return fmt.Errorf("регион %s: %w", r.Name, err)
+			}
+		}
+	}
+	if err := s.Delivery.Validate(); err != nil {
+		return fmt.Errorf("модель доставки: %w", err)
+	}
+	if err := s.Logistics.Validate(); err != nil {
+		return fmt.Errorf("параметры загрузки: %w", err)
+	}
+	if err := s.Schedule.Validate(); err != nil {
+		return fmt.Errorf("расписание доставки: %w", err)
+	}
+	if err := domain.ValidateBoxes(s.Boxes); err != nil {
+		return fmt.Errorf("коробки: %w", err)
+	}
+	if err := s.Composition.Validate(); err != nil {
+		return fmt.Errorf("правила состава: %w", err)
+	}
+	if err := domain.ValidateTemplates(s.Templates); err != nil {
+		return fmt.Errorf("шаблоны подарков: %w", err)
+	}
+	if err := s.Household.Validate(); err != nil {
+		return fmt.Errorf("правила для семей: %w", err)
+	}
+	if err := s.History.Validate(); err != nil {
+		return fmt.Errorf("история подарков: %w", err)
+	}
+	if err := s.Severity.Validate(); err != nil {
+		return fmt.Errorf("строгость требований: %w", err)
+	}
+	if s.BaseCurrency != "" && !domain.ValidCurrency(s.BaseCurrency) {
+		return fmt.Errorf("некорректный код базовой валюты: %q", s.BaseCurrency)
+	}
+	if err := s.SafetyPolicy.Validate(); err != nil {
+		return fmt.Errorf("политика безопасности: %w", err)
+	}
+	return nil
+}
+
+// DeliveryTariffs возвращает тарифы доставки регионов, для которых они заданы.
+func (s Settings) DeliveryTariffs() map[string]domain.DeliveryTariff {
+	result := make(map[string]domain.DeliveryTariff)
+	for _, r := range s.Regions {
+		if r.Delivery != nil {
+			result[r.Name] = *r.Delivery
+		}
+	}
+	return result
+}
+
+// Coefficients возвращает региональные коэффициенты по названию региона.
+func (s Settings) Coefficients() map[string]float64 {
+	result := make(map[string]float64, len(s.Regions))
+	for _, r := range s.Regions {
+		result[r.Name] = r.Coefficient
+	}
+	return result
+}
+
+func (s Settings) resolvePaths(dir string) Settings {
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	s.Children = resolve(s.Children)
+	s.Catalog = resolve(s.Catalog)
+	s.Wishes = resolve(s.Wishes)
+	s.RegionsFile = resolve(s.RegionsFile)
+	s.HistoryFile = resolve(s.HistoryFile)
+	s.NotesDictionary = resolve(s.NotesDictionary)
+	s.Keywords = resolve(s.Keywords)
+	s.Rules = resolve(s.Rules)
+	s.Certifications = resolve(s.Certifications)
+	s.ExchangeRates = resolve(s.ExchangeRates)
+	return s
+}
@@ -0,0 +1,243 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"giftcalc/internal/domain"
+	"giftcalc/internal/infrastructure/schema"
+)
+
+// Backend выполняет операции API тем же кодом, что и команды CLI.
+type Backend interface {
+	// Calculate выполняет расчет по данным запроса.
+	Calculate(req CalculateRequest) (domain.Report, error)
+
+	// Validate проверяет документ по схеме и содержимому.
+	Validate(kind schema.Kind, data []byte) error
+}
+
+// CalculateRequest - тело запроса POST /calculate. Данные детей, каталога и
+// пожеланий передаются в теле (в формате файлов данных) или путями внутри
+// каталога данных сервера; если не передано ни то, ни другое, используются
+// файлы из настроек сервера.
+type CalculateRequest struct {
+	Children json.RawMessage `json:"children,omitempty"`
+	Catalog  json.RawMessage `json:"catalog,omitempty"`
+	Wishes   json.RawMessage `json:"wishes,omitempty"`
+
+	ChildrenFile string `json:"children_file,omitempty"`
+	CatalogFile  string `json:"catalog_file,omitempty"`
+	WishesFile   string `json:"wishes_file,omitempty"`
+
+	Options CalculateOptions `json:"options,omitempty"`
+}
+
+// CalculateOptions - параметры расчета, перекрывающие настройки сервера.
+type CalculateOptions struct {
+	MaxBudget domain.Money `json:"max_budget,omitempty"`
+	MaxCount  int          `json:"max_count,omitempty"`
+	MaxWeight float64      `json:"max_weight,omitempty"`
+	Strategy  string       `json:"strategy,omitempty"`
+	Mode      string       `json:"mode,omitempty"`
+	Explain   bool         `json:"explain,omitempty"`
+}
+
+// CalculateResponse - ответ POST /calculate.
+type CalculateResponse struct {
+	ID     string        `json:"id"`
+	Report domain.Report `json:"report"`
+}
+
+// ValidateResponse - ответ POST /validate.
+type ValidateResponse struct {
+	Kind       schema.Kind              `json:"kind"`
+	Valid      bool                     `json:"valid"`
+	Error      string                   `json:"error,omitempty"`
+	Violations []schema.ValidationError `json:"violations,omitempty"`
+}
+
+// Error - ошибка запроса с HTTP статусом. Нарушения схемы передаются клиенту списком.
+type Error struct {
+	Status     int                      `json:"-"`
+	Message    string                   `json:"error"`
+	Violations []schema.ValidationError `json:"violations,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Options - ограничения сервера.
+type Options struct {
+	// MaxBodyBytes - максимальный размер тела запроса.
+	MaxBodyBytes int64
+
+	// MaxReports - сколько последних отчетов хранится для GET /reports/{id}.
+	MaxReports int
+}
+
+// Server обслуживает JSON API расчета подарков. Отчеты хранятся в памяти.
+type Server struct {
+	backend Backend
+	opts    Options
+
+	mu      sync.Mutex
+	reports map[string]domain.Report
+	order   []string
+}
+
+// New создает сервер API.
+func New(backend Backend, opts Options) *Server {
+	return &Server{
+		backend: backend,
+		opts:    opts,
+		reports: make(map[string]domain.Report),
+	}
+}
+
+// Handler возвращает обработчик запросов с журналом доступа.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /calculate", s.handleCalculate)
+	mux.HandleFunc("GET /requirements", s.handleRequirements)
+	mux.HandleFunc("POST /validate", s.handleValidate)
+	mux.HandleFunc("GET /reports/{id}", s.handleReport)
+	return accessLog(mux)
+}
+
+func (s *Server) handleCalculate(w http.ResponseWriter, r *http.Request) {
+	var req CalculateRequest
+	if err := s.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	report, err := s.backend.Calculate(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id := s.store(report)
+	slog.Info("Расчет выполнен",
+		slog.String("report_id", id),
+		slog.Int("children", len(report.Results)),
+	)
+
+	w.Header().Set("Location", "/reports/"+id)
+	writeJSON(w, http.StatusCreated, CalculateResponse{ID: id, Report: report})
+}
+
+func (s *Server) handleRequirements(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, domain.GetAllRequirements())
+}
+
+// handleValidate проверяет тело запроса как файл данных вида ?kind=.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	kind, err := schema.ParseKind(r.URL.Query().Get("kind"))
+	if err != nil {
+		writeError(w, &Error{Status: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	var data json.RawMessage
+	if err := s.decode(w, r, &data); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := ValidateResponse{Kind: kind, Valid: true}
+	if err := s.backend.Validate(kind, data); err != nil {
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnprocessableEntity {
+			writeError(w, err)
+			return
+		}
+		resp.Valid = false
+		resp.Error = apiErr.Message
+		resp.Violations = apiErr.Violations
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	report, ok := s.reports[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, &Error{Status: http.StatusNotFound, Message: fmt.Sprintf("отчет %s не найден", id)})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// decode читает JSON тело запроса с ограничением размера.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v any) error {
+	if s.opts.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &Error{
+				Status:  http.StatusRequestEntityTooLarge,
+				Message: fmt.Sprintf("тело запроса больше %d байт", tooLarge.Limit),
+			}
+		}
+		return &Error{Status: http.StatusBadRequest, Message: "некорректный JSON: " + err.Error()}
+	}
+	return nil
+}
+
+// store сохраняет отчет и вытесняет самые старые сверх MaxReports.
+func (s *Server) store(report domain.Report) string {
+	id := newID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports[id] = report
+	s.order = append(s.order, id)
+	for s.opts.MaxReports > 0 && len(s.order) > s.opts.MaxReports {
+		delete(s.reports, s.order[0])
+		s.order = s.order[1:]
+	}
+	return id
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Не смог записать ответ", slog.String("err", err.Error()))
+	}
+}
+
+// writeError отвечает ошибкой; ошибки без статуса считаются внутренними.
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		slog.Error("Ошибка обработки запроса", slog.String("err", err.Error()))
+		apiErr = &Error{Status: http.StatusInternalServerError, Message: "внутренняя ошибка сервера"}
+	}
+	writeJSON(w, apiErr.Status, apiErr)
+}
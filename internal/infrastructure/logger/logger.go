@@ -1,6 +1,12 @@
 package logger
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -8,51 +14,118 @@ import (
 var (
 	globalLogger *zap.Logger
 	sugarLogger  *zap.SugaredLogger
+	runID        string
 )
 
-// Init инициализирует глобальный логгер
-func Init(level string, development bool) error {
-	var zapLevel zapcore.Level
-	switch level {
-	case "debug":
+// Options содержит параметры логирования из флагов командной строки.
+type Options struct {
+	// Level - уровень логирования (debug, info, warn, error, fatal).
+	Level string
+
+	// Format - формат вывода: "json" или "console".
+	Format string
+
+	// File - файл для записи логов. Пустая строка означает stderr.
+	File string
+
+	// Verbose включает уровень debug независимо от Level.
+	Verbose bool
+
+	// MaxSizeMB - размер файла в мегабайтах, после которого он ротируется.
+	MaxSizeMB int
+
+	// MaxBackups - сколько ротированных файлов хранить.
+	MaxBackups int
+}
+
+// Init инициализирует глобальный логгер и направляет в него log/slog.
+// Каждая строка лога получает поле run_id текущего запуска.
+func Init(opts Options) error {
+	zapLevel, err := parseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	if opts.Verbose {
 		zapLevel = zapcore.DebugLevel
-	case "info":
-		zapLevel = zapcore.InfoLevel
-	case "warn":
-		zapLevel = zapcore.WarnLevel
-	case "error":
-		zapLevel = zapcore.ErrorLevel
-	case "fatal":
-		zapLevel = zapcore.FatalLevel
-	default:
-		zapLevel = zapcore.InfoLevel
 	}
 
-	var config zap.Config
-	if development {
-		config = zap.NewDevelopmentConfig()
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	} else {
-		config = zap.NewProductionConfig()
-		config.EncoderConfig.TimeKey = "timestamp"
-		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var sink zapcore.WriteSyncer = zapcore.Lock(os.Stderr)
+	if opts.File != "" {
+		file, err := NewRotatingFile(opts.File, int64(opts.MaxSizeMB)*1024*1024, opts.MaxBackups)
+		if err != nil {
+			return err
+		}
+		sink = zapcore.Lock(file)
 	}
 
-	config.Level = zap.NewAtomicLevelAt(zapLevel)
-	config.OutputPaths = []string{"stdout"}
-	config.ErrorOutputPaths = []string{"stderr"}
-
-	logger, err := config.Build()
+	encoder, err := newEncoder(opts.Format, opts.File == "")
 	if err != nil {
 		return err
 	}
 
+	runID = newRunID()
+	core := zapcore.NewCore(encoder, sink, zap.NewAtomicLevelAt(zapLevel))
+	logger := zap.New(core,
+		zap.AddCaller(),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	).With(zap.String("run_id", runID))
+
 	globalLogger = logger
 	sugarLogger = logger.Sugar()
+	slog.SetDefault(slog.New(NewSlogHandler(logger.Core())))
 
 	return nil
 }
 
+func parseLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "", "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	case "fatal":
+		return zapcore.FatalLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("неизвестный уровень логирования: %s", level)
+	}
+}
+
+func newEncoder(format string, colored bool) (zapcore.Encoder, error) {
+	switch format {
+	case "json":
+		config := zap.NewProductionEncoderConfig()
+		config.TimeKey = "timestamp"
+		config.EncodeTime = zapcore.ISO8601TimeEncoder
+		return zapcore.NewJSONEncoder(config), nil
+	case "", "console":
+		config := zap.NewDevelopmentEncoderConfig()
+		config.EncodeTime = zapcore.ISO8601TimeEncoder
+		if colored {
+			config.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(config), nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов: %s", format)
+	}
+}
+
+func newRunID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// RunID возвращает идентификатор текущего запуска.
+func RunID() string {
+	return runID
+}
+
 // Get возвращает логгер
 func Get() *zap.Logger {
 	if globalLogger == nil {
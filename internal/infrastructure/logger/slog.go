@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler - реализация slog.Handler поверх zapcore.Core.
+// Позволяет командам логировать через log/slog, а запись вести через zap.
+type SlogHandler struct {
+	core   zapcore.Core
+	prefix string
+}
+
+// NewSlogHandler создает обработчик slog, пишущий в переданное ядро zap.
+func NewSlogHandler(core zapcore.Core) *SlogHandler {
+	return &SlogHandler{core: core}
+}
+
+// Enabled сообщает, будет ли записано сообщение указанного уровня.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevel(level))
+}
+
+// Handle записывает сообщение slog в zap.
+func (h *SlogHandler) Handle(_ context.Context, record slog.Record) error {
+	entry := zapcore.Entry{
+		Level:   zapLevel(record.Level),
+		Time:    record.Time,
+		Message: record.Message,
+	}
+
+	if record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+		entry.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	}
+
+	checked := h.core.Check(entry, nil)
+	if checked == nil {
+		return nil
+	}
+
+	fields := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = h.appendAttr(fields, h.prefix, attr)
+		return true
+	})
+	checked.Write(fields...)
+
+	return nil
+}
+
+// WithAttrs возвращает обработчик с постоянными полями.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = h.appendAttr(fields, h.prefix, attr)
+	}
+	return &SlogHandler{core: h.core.With(fields), prefix: h.prefix}
+}
+
+// WithGroup возвращает обработчик, добавляющий префикс группы к ключам.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{core: h.core, prefix: h.prefix + name + "."}
+}
+
+func (h *SlogHandler) appendAttr(fields []zap.Field, prefix string, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	key := prefix + attr.Key
+	value := attr.Value
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = key + "."
+		}
+		for _, a := range value.Group() {
+			fields = h.appendAttr(fields, groupPrefix, a)
+		}
+		return fields
+	case slog.KindString:
+		return append(fields, zap.String(key, value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(key, value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(key, value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(key, value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(key, value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(key, value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(key, value.Time()))
+	default:
+		if err, ok := value.Any().(error); ok {
+			return append(fields, zap.NamedError(key, err))
+		}
+		return append(fields, zap.Any(key, value.Any()))
+	}
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
@@ -0,0 +1,171 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"giftcalc/internal/domain"
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	moneyType = reflect.TypeOf(domain.Money(0))
+)
+
+// generator строит схему по типам Go через reflection.
+// Именованные структуры выносятся в $defs и подключаются через $ref.
+type generator struct {
+	defs  map[string]Schema
+	enums map[reflect.Type][]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		defs:  make(map[string]Schema),
+		enums: enums(),
+	}
+}
+
+func (g *generator) schemaFor(t reflect.Type) Schema {
+	if values, ok := g.enums[t]; ok {
+		enum := make([]any, len(values))
+		for i, v := range values {
+			enum[i] = v
+		}
+		return Schema{"type": "string", "enum": enum}
+	}
+
+	// Денежные суммы хранятся в копейках, но в JSON записываются десятичным числом
+	if t == moneyType {
+		return Schema{"type": "number"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaFor(t.Elem()))
+	case reflect.Struct:
+		if t == timeType {
+			return Schema{"type": "string", "format": "date-time"}
+		}
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := g.defs[name]; !ok {
+			// Резервируем имя до обхода полей, чтобы не зациклиться на рекурсивных типах
+			g.defs[name] = Schema{}
+			g.defs[name] = g.structSchema(t)
+		}
+		return Schema{"$ref": "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": []any{"array", "null"}, "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	default:
+		return Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) Schema {
+	properties := make(map[string]Schema)
+	var required []any
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _ := parseJSONTag(field)
+		if name == "-" {
+			continue
+		}
+
+		prop := g.schemaFor(field.Type)
+		if applyConstraints(prop, field.Tag.Get("jsonschema")) {
+			required = append(required, name)
+		}
+		properties[name] = prop
+	}
+
+	s := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// applyConstraints переносит ограничения из тега jsonschema в схему поля.
+// Формат тега: `jsonschema:"required,minimum=0,maximum=18,minLength=1"`.
+// Возвращает true если поле обязательное.
+func applyConstraints(s Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "required":
+			required = true
+		case "minimum", "maximum", "exclusiveMinimum":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				s[key] = n
+			}
+		case "minLength", "minItems":
+			if n, err := strconv.Atoi(value); err == nil {
+				s[key] = n
+			}
+		}
+	}
+	return required
+}
+
+func parseJSONTag(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+func nullable(s Schema) Schema {
+	if enum, ok := s["enum"].([]any); ok {
+		s["enum"] = append(enum, nil)
+	}
+
+	switch t := s["type"].(type) {
+	case string:
+		s["type"] = []any{t, "null"}
+		return s
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				return s
+			}
+		}
+		s["type"] = append(t, "null")
+		return s
+	}
+	return Schema{"anyOf": []any{s, Schema{"type": "null"}}}
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// frame описывает текущий контейнер при потоковом разборе JSON.
+type frame struct {
+	object    bool
+	key       string
+	index     int
+	expectKey bool
+}
+
+// locate проходит документ потоково и запоминает смещение начала
+// каждого значения по его JSON Pointer.
+func locate(data []byte) map[string]int64 {
+	offsets := make(map[string]int64)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var stack []frame
+
+	advance := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.object {
+			top.expectKey = true
+		} else {
+			top.index++
+		}
+	}
+
+	for {
+		start := skipSeparators(data, dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			advance()
+			continue
+		}
+
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.object && top.expectKey {
+				top.key, _ = tok.(string)
+				top.expectKey = false
+				continue
+			}
+		}
+
+		offsets[pointerOf(stack)] = start
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, frame{object: true, expectKey: true})
+		case json.Delim('['):
+			stack = append(stack, frame{})
+		default:
+			advance()
+		}
+	}
+
+	return offsets
+}
+
+func pointerOf(stack []frame) string {
+	var b strings.Builder
+	for _, f := range stack {
+		b.WriteByte('/')
+		if f.object {
+			b.WriteString(escapePointer(f.key))
+		} else {
+			b.WriteString(strconv.Itoa(f.index))
+		}
+	}
+	return b.String()
+}
+
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineColumn переводит байтовое смещение в номер строки и столбца (с единицы).
+// Столбец считается в символах, а не в байтах, чтобы совпадать с редакторами.
+func lineColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	column := len([]rune(string(before[lineStart:]))) + 1
+	return line, column
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"giftcalc/internal/domain"
+)
+
+// Kind определяет вид файла данных, для которого строится схема.
+type Kind string
+
+const (
+	KindChildren       Kind = "children"
+	KindCatalog        Kind = "catalog"
+	KindWishes         Kind = "wishes"
+	KindRegions        Kind = "regions"
+	KindReport         Kind = "report"
+	KindHistory        Kind = "history"
+	KindScenario       Kind = "scenario"
+	KindNotes          Kind = "notes"
+	KindKeywords       Kind = "keywords"
+	KindRules          Kind = "rules"
+	KindCertifications Kind = "certifications"
+	KindRates          Kind = "rates"
+)
+
+// Schema представляет JSON Schema документ.
+type Schema map[string]any
+
+// Draft - версия спецификации JSON Schema.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+type kindInfo struct {
+	typ   reflect.Type
+	title string
+}
+
+var kinds = map[Kind]kindInfo{
+	KindChildren:       {reflect.TypeOf(domain.ChildrenData{}), "Список детей"},
+	KindCatalog:        {reflect.TypeOf(domain.CatalogData{}), "Каталог подарков"},
+	KindWishes:         {reflect.TypeOf([]domain.Wish{}), "Пожелания детей"},
+	KindRegions:        {reflect.TypeOf([]domain.Region{}), "Региональные коэффициенты"},
+	KindReport:         {reflect.TypeOf(domain.Report{}), "Отчет о расчете подарков"},
+	KindHistory:        {reflect.TypeOf(domain.GiftHistory{}), "История подарков прошлых лет"},
+	KindScenario:       {reflect.TypeOf(domain.ScenarioGrid{}), "Сетка сценариев"},
+	KindNotes:          {reflect.TypeOf(domain.NotesDictionary{}), "Словарь разбора заметок"},
+	KindKeywords:       {reflect.TypeOf(domain.KeywordDictionary{}), "Словарь ключевых слов"},
+	KindRules:          {reflect.TypeOf(domain.RulesDictionary{}), "Правила соответствия требованиям"},
+	KindCertifications: {reflect.TypeOf(domain.CertificationRegistry{}), "Реестр сертификатов"},
+	KindRates:          {reflect.TypeOf(domain.ExchangeRates{}), "Таблица курсов валют"},
+}
+
+// Kinds возвращает список поддерживаемых видов файлов.
+func Kinds() []Kind {
+	result := make([]Kind, 0, len(kinds))
+	for k := range kinds {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// ParseKind проверяет название вида файла.
+func ParseKind(s string) (Kind, error) {
+	k := Kind(s)
+	if _, ok := kinds[k]; !ok {
+		return "", fmt.Errorf("неизвестный вид схемы: %s (допустимо: %v)", s, Kinds())
+	}
+	return k, nil
+}
+
+// Generate строит JSON Schema для указанного вида файла.
+// Перечисления требований берутся из domain.GetAllRequirements.
+func Generate(kind Kind) (Schema, error) {
+	info, ok := kinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный вид схемы: %s", kind)
+	}
+
+	g := newGenerator()
+	root := g.schemaFor(info.typ)
+	root["$schema"] = Draft
+	root["$id"] = "https://giftcalc.local/schema/" + string(kind) + ".json"
+	root["title"] = info.title
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+
+	return root, nil
+}
+
+// enums возвращает допустимые значения для строковых типов домена.
+func enums() map[reflect.Type][]string {
+	all := domain.GetAllRequirements()
+
+	return map[reflect.Type][]string{
+		reflect.TypeOf(domain.DietaryRequirement("")): all["dietary"],
+		reflect.TypeOf(domain.SafetyRequirement("")):  all["safety"],
+		reflect.TypeOf(domain.MedicalRequirement("")): all["medical"],
+		reflect.TypeOf(domain.OtherRequirement("")):   all["other"],
+		reflect.TypeOf(domain.SelectionOutcome("")): {
+			string(domain.OutcomeAccepted),
+			string(domain.OutcomeRejectedAge),
+			string(domain.OutcomeRejectedRequirement),
+			string(domain.OutcomeRejectedComposition),
+			string(domain.OutcomeRejectedHistory),
+			string(domain.OutcomeSkippedBudget),
+			string(domain.OutcomeSkippedCount),
+			string(domain.OutcomeSkippedWeight),
+			string(domain.OutcomeSkippedStock),
+		},
+		reflect.TypeOf(domain.WishPriority("")): {
+			string(domain.PriorityHigh),
+			string(domain.PriorityMedium),
+			string(domain.PriorityLow),
+		},
+	}
+}
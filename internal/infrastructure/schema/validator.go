@@ -0,0 +1,410 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError описывает нарушение схемы с точным местом в файле.
+type ValidationError struct {
+	// Pointer - JSON Pointer на ошибочное значение, например /children/3/special_requirements/dietary/0
+	Pointer string `json:"pointer"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("строка %d, столбец %d (%s): %s", e.Line, e.Column, pointer, e.Message)
+}
+
+// ValidateDocument проверяет JSON документ по схеме указанного вида.
+// Возвращает ошибку если документ не является корректным JSON,
+// и список нарушений схемы в порядке их следования в файле.
+func ValidateDocument(kind Kind, data []byte) ([]ValidationError, error) {
+	s, err := Generate(kind)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(s, data)
+}
+
+// Validate проверяет JSON документ по переданной схеме.
+func Validate(s Schema, data []byte) ([]ValidationError, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := lineColumn(data, syntaxErr.Offset)
+			return nil, fmt.Errorf("строка %d, столбец %d: некорректный JSON: %w", line, col, err)
+		}
+		return nil, fmt.Errorf("некорректный JSON: %w", err)
+	}
+
+	v := &validator{root: s}
+	v.validate(s, doc, "")
+
+	offsets := locate(data)
+	for i := range v.errs {
+		offset, ok := offsets[v.errs[i].Pointer]
+		if !ok {
+			continue
+		}
+		v.errs[i].Line, v.errs[i].Column = lineColumn(data, offset)
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].Line != v.errs[j].Line {
+			return v.errs[i].Line < v.errs[j].Line
+		}
+		return v.errs[i].Column < v.errs[j].Column
+	})
+
+	return v.errs, nil
+}
+
+type validator struct {
+	root Schema
+	errs []ValidationError
+}
+
+func (v *validator) fail(pointer, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(s Schema, value any, pointer string) {
+	if ref, ok := s["$ref"].(string); ok {
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			v.fail(pointer, "%v", err)
+			return
+		}
+		v.validate(resolved, value, pointer)
+		return
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		if !v.matchesAny(anyOf, value, pointer) {
+			return
+		}
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		v.fail(pointer, "ожидался тип %s, получено %s", typeNames(t), jsonType(value))
+		return
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !inEnum(enum, value) {
+		v.fail(pointer, "%s", enumMessage(enum, value))
+		return
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(s, val, pointer)
+	case []any:
+		if items := asSchema(s["items"]); items != nil {
+			for i, item := range val {
+				v.validate(items, item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+		if n, ok := number(s["minItems"]); ok && float64(len(val)) < n {
+			v.fail(pointer, "требуется не менее %v элементов", n)
+		}
+	case json.Number:
+		f, _ := val.Float64()
+		if n, ok := number(s["minimum"]); ok && f < n {
+			v.fail(pointer, "значение %s меньше минимально допустимого %v", val, n)
+		}
+		if n, ok := number(s["exclusiveMinimum"]); ok && f <= n {
+			v.fail(pointer, "значение %s должно быть больше %v", val, n)
+		}
+		if n, ok := number(s["maximum"]); ok && f > n {
+			v.fail(pointer, "значение %s больше максимально допустимого %v", val, n)
+		}
+	case string:
+		if n, ok := number(s["minLength"]); ok && float64(len([]rune(val))) < n {
+			v.fail(pointer, "строка короче %v символов", n)
+		}
+		if s["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, val); err != nil {
+				v.fail(pointer, "ожидалась дата в формате RFC 3339, получено %q", val)
+			}
+		}
+	}
+}
+
+func (v *validator) validateObject(s Schema, obj map[string]any, pointer string) {
+	properties := asSchemaMap(s["properties"])
+
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				v.fail(pointer, "отсутствует обязательное поле %q", name)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := pointer + "/" + escapePointer(key)
+		if prop, ok := properties[key]; ok {
+			v.validate(prop, obj[key], child)
+			continue
+		}
+
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.fail(child, "%s", unknownFieldMessage(key, properties))
+			}
+		default:
+			if es := asSchema(extra); es != nil {
+				v.validate(es, obj[key], child)
+			}
+		}
+	}
+}
+
+// matchesAny проверяет значение по вариантам anyOf.
+// Если ни один вариант не подошел, сохраняет ошибки самого близкого из них.
+func (v *validator) matchesAny(variants []any, value any, pointer string) bool {
+	var best []ValidationError
+	for i, variant := range variants {
+		sub := &validator{root: v.root}
+		sub.validate(asSchema(variant), value, pointer)
+		if len(sub.errs) == 0 {
+			return true
+		}
+		if i == 0 || len(sub.errs) < len(best) {
+			best = sub.errs
+		}
+	}
+	v.errs = append(v.errs, best...)
+	return false
+}
+
+func (v *validator) resolve(ref string) (Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемая ссылка %s", ref)
+	}
+	if s := asSchemaMap(v.root["$defs"])[name]; s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("не найдено определение %s", ref)
+}
+
+func asSchema(v any) Schema {
+	switch s := v.(type) {
+	case Schema:
+		return s
+	case map[string]any:
+		return s
+	}
+	return nil
+}
+
+func asSchemaMap(v any) map[string]Schema {
+	switch m := v.(type) {
+	case map[string]Schema:
+		return m
+	case map[string]any:
+		result := make(map[string]Schema, len(m))
+		for k, s := range m {
+			result[k] = asSchema(s)
+		}
+		return result
+	}
+	return nil
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func jsonType(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if isInteger(val) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+func isInteger(n json.Number) bool {
+	if _, err := n.Int64(); err == nil {
+		return true
+	}
+	f, err := n.Float64()
+	return err == nil && f == math.Trunc(f)
+}
+
+func matchesType(t any, value any) bool {
+	actual := jsonType(value)
+	check := func(name string) bool {
+		return name == actual || (name == "number" && actual == "integer")
+	}
+
+	switch tt := t.(type) {
+	case string:
+		return check(tt)
+	case []any:
+		for _, name := range tt {
+			if s, ok := name.(string); ok && check(s) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeNames(t any) string {
+	switch tt := t.(type) {
+	case string:
+		return tt
+	case []any:
+		names := make([]string, len(tt))
+		for i, n := range tt {
+			names[i] = fmt.Sprint(n)
+		}
+		return strings.Join(names, " | ")
+	}
+	return fmt.Sprint(t)
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, e := range enum {
+		if e == nil && value == nil {
+			return true
+		}
+		if s, ok := value.(string); ok && e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func enumMessage(enum []any, value any) string {
+	allowed := make([]string, 0, len(enum))
+	for _, e := range enum {
+		if e != nil {
+			allowed = append(allowed, fmt.Sprint(e))
+		}
+	}
+
+	msg := fmt.Sprintf("недопустимое значение %v, допустимо: %s", quote(value), strings.Join(allowed, ", "))
+	if s, ok := value.(string); ok {
+		if hint := closest(s, allowed); hint != "" {
+			msg += fmt.Sprintf(" (возможно, имелось в виду %q)", hint)
+		}
+	}
+	return msg
+}
+
+func unknownFieldMessage(key string, properties map[string]Schema) string {
+	known := make([]string, 0, len(properties))
+	for k := range properties {
+		known = append(known, k)
+	}
+	sort.Strings(known)
+
+	msg := fmt.Sprintf("неизвестное поле %q", key)
+	if hint := closest(key, known); hint != "" {
+		msg += fmt.Sprintf(" (возможно, имелось в виду %q)", hint)
+	}
+	return msg
+}
+
+func quote(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// closest ищет наиболее похожее допустимое значение для подсказки об опечатке.
+func closest(value string, candidates []string) string {
+	best := ""
+	bestDist := math.MaxInt
+	for _, c := range candidates {
+		d := levenshtein(value, c)
+		if d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	if bestDist > 0 && bestDist <= max(2, len([]rune(value))/3) {
+		return best
+	}
+	return ""
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}